@@ -0,0 +1,185 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Strategy identifies which limit-calculation strategy a policy selects.
+// +kubebuilder:validation:Enum=percent;fit;cap;colocation
+type Strategy string
+
+const (
+	StrategyPercent    Strategy = "percent"
+	StrategyFit        Strategy = "fit"
+	StrategyCap        Strategy = "cap"
+	StrategyColocation Strategy = "colocation"
+)
+
+// PolicyRule holds the tunables that are common to both the cluster-scoped
+// NodeFitPolicy and the namespace-scoped NamespaceNodeFitPolicy. It mirrors
+// the knobs that used to live solely on pod annotations.
+type PolicyRule struct {
+	// WorkloadSelector narrows the policy to pods whose labels match. An empty
+	// selector matches every pod in scope.
+	// +optional
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+
+	// ContainerNames restricts which containers in a matched pod receive
+	// adjusted limits. Empty means all containers.
+	// +optional
+	ContainerNames []string `json:"containerNames,omitempty"`
+
+	// Resources lists which resource types this policy manages. Defaults to
+	// memory and cpu when omitted.
+	// +optional
+	Resources []corev1.ResourceName `json:"resources,omitempty"`
+
+	// Strategy selects the limit-calculation strategy.
+	// +kubebuilder:default=percent
+	Strategy Strategy `json:"strategy,omitempty"`
+
+	// Percent is the percent-strategy knob; see the percent annotation for
+	// semantics.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percent int `json:"percent,omitempty"`
+
+	// Buffer is the fit-strategy headroom reserved on the node.
+	// +optional
+	Buffer *resource.Quantity `json:"buffer,omitempty"`
+
+	// Priority determines precedence when multiple policies match the same
+	// pod; higher values win. Policies with equal priority are ordered by
+	// name for determinism.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// HighWatermark is the colocation-strategy ceiling, as a percent of node
+	// allocatable, that actual usage plus the pod's new limit is allowed to
+	// reach. Only used by the colocation strategy.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	HighWatermark int `json:"highWatermark,omitempty"`
+
+	// ReclaimRatio is the percent of computed headroom the colocation
+	// strategy actually hands to the pod, so operators can hold some of the
+	// reclaimed capacity back as extra safety margin.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ReclaimRatio int `json:"reclaimRatio,omitempty"`
+
+	// DegradePolicy is the strategy the colocation strategy falls back to
+	// when live node metrics are unavailable or stale.
+	// +kubebuilder:validation:Enum=fit;cap
+	// +optional
+	DegradePolicy Strategy `json:"degradePolicy,omitempty"`
+}
+
+// NodeFitPolicySpec defines a cluster-wide or namespace-scoped policy.
+type NodeFitPolicySpec struct {
+	// NamespaceSelector restricts the policy to namespaces matching the
+	// selector. An empty selector matches every namespace in the cluster.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	PolicyRule `json:",inline"`
+}
+
+// NodeFitPolicyStatus reports the result of validating and applying a policy.
+type NodeFitPolicyStatus struct {
+	// ObservedGeneration is the generation most recently reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions surfaces validation results, e.g. Accepted=False when a
+	// selector does not compile. Overlaps or conflicts with other policies
+	// are not reported here.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=nfp
+// +kubebuilder:printcolumn:name="Strategy",type=string,JSONPath=`.spec.strategy`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeFitPolicy declares how nodefit-controller should resize pods across
+// the whole cluster, optionally restricted to a set of namespaces.
+type NodeFitPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeFitPolicySpec   `json:"spec,omitempty"`
+	Status NodeFitPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeFitPolicyList contains a list of NodeFitPolicy.
+type NodeFitPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeFitPolicy `json:"items"`
+}
+
+// NamespaceNodeFitPolicySpec defines a policy scoped to the namespace it
+// lives in. It has no NamespaceSelector since its namespace already is the
+// scope.
+type NamespaceNodeFitPolicySpec struct {
+	PolicyRule `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=nnfp
+// +kubebuilder:printcolumn:name="Strategy",type=string,JSONPath=`.spec.strategy`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NamespaceNodeFitPolicy declares how nodefit-controller should resize pods
+// within the namespace it is created in. It always takes precedence over a
+// cluster-scoped NodeFitPolicy matching the same namespace, see PolicyIndex.
+type NamespaceNodeFitPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceNodeFitPolicySpec `json:"spec,omitempty"`
+	Status NodeFitPolicyStatus        `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceNodeFitPolicyList contains a list of NamespaceNodeFitPolicy.
+type NamespaceNodeFitPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceNodeFitPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeFitPolicy{}, &NodeFitPolicyList{})
+	SchemeBuilder.Register(&NamespaceNodeFitPolicy{}, &NamespaceNodeFitPolicyList{})
+}
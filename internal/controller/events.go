@@ -0,0 +1,131 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// AnnotationLastAdjustment records the JSON-encoded lastAdjustmentRecord for
+// the most recent limit change, so operators can see what happened without
+// digging through controller logs or Events, which roll off.
+const AnnotationLastAdjustment = "nodefit.io/last-adjustment"
+
+// containerAdjustment is the per-container entry of a lastAdjustmentRecord.
+type containerAdjustment struct {
+	Old corev1.ResourceList `json:"old,omitempty"`
+	New corev1.ResourceList `json:"new"`
+}
+
+// lastAdjustmentRecord is the structure stored, as JSON, in
+// AnnotationLastAdjustment.
+type lastAdjustmentRecord struct {
+	Timestamp  time.Time                      `json:"timestamp"`
+	Strategy   string                         `json:"strategy"`
+	Reason     string                         `json:"reason"`
+	Containers map[string]containerAdjustment `json:"containers"`
+}
+
+// buildLastAdjustmentAnnotation captures each adjusted container's limits
+// before and after newLimits is applied, encoded for AnnotationLastAdjustment.
+func buildLastAdjustmentAnnotation(pod *corev1.Pod, newLimits map[string]corev1.ResourceList, strategy, reason string, now time.Time) (string, error) {
+	rec := lastAdjustmentRecord{
+		Timestamp:  now,
+		Strategy:   strategy,
+		Reason:     reason,
+		Containers: make(map[string]containerAdjustment, len(newLimits)),
+	}
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		limits, ok := newLimits[container.Name]
+		if !ok {
+			continue
+		}
+		rec.Containers[container.Name] = containerAdjustment{
+			Old: container.Resources.Limits,
+			New: limits,
+		}
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseLastAdjustmentRecord decodes pod's AnnotationLastAdjustment, the
+// inverse of buildLastAdjustmentAnnotation, so a later reconcile can recover
+// the pre-adjustment limits to roll back to.
+func parseLastAdjustmentRecord(pod *corev1.Pod) (lastAdjustmentRecord, error) {
+	var rec lastAdjustmentRecord
+	raw, ok := pod.Annotations[AnnotationLastAdjustment]
+	if !ok {
+		return rec, fmt.Errorf("nodefit: pod has no %s annotation to roll back from", AnnotationLastAdjustment)
+	}
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
+
+// recordAdjustedEvent emits Adjusted, or Capped for the cap strategy, once a
+// patch has been accepted.
+func recordAdjustedEvent(recorder record.EventRecorder, pod *corev1.Pod, strategy string) {
+	if recorder == nil {
+		return
+	}
+	if strategy == StrategyCap {
+		recorder.Eventf(pod, corev1.EventTypeNormal, "Capped", "nodefit: limits capped to requests (strategy=%s)", strategy)
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeNormal, "Adjusted", "nodefit: limits adjusted (strategy=%s)", strategy)
+}
+
+// recordFailedResizeEvent emits FailedResize when applying the new limits
+// was rejected by the API server or kubelet.
+func recordFailedResizeEvent(recorder record.EventRecorder, pod *corev1.Pod, err error) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeWarning, "FailedResize", "nodefit: failed to apply new limits: %v", err)
+}
+
+// recordResizeUnsupportedEvent emits ResizeUnsupported when the API server
+// doesn't support the pods/resize subresource, so nodefit skips the patch
+// instead of risking a rejection or an unwanted container restart.
+func recordResizeUnsupportedEvent(recorder record.EventRecorder, pod *corev1.Pod) {
+	if recorder == nil {
+		return
+	}
+	recorder.Event(pod, corev1.EventTypeWarning, "ResizeUnsupported", "nodefit: cluster does not support in-place pod resize, skipping adjustment")
+}
+
+// recordResizeRejectedEvent emits ResizeRejected when the kubelet reports a
+// Deferred or Infeasible outcome for a resize request.
+func recordResizeRejectedEvent(recorder record.EventRecorder, pod *corev1.Pod, status string) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(pod, corev1.EventTypeWarning, "ResizeRejected", "nodefit: in-place resize was %s by the kubelet", status)
+}
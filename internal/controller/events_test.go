@@ -0,0 +1,179 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/KamorionLabs/node-fit-controller/internal/policy"
+)
+
+func TestBuildAndParseLastAdjustmentAnnotationRoundTrip(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			}},
+		},
+	}
+	newLimits := map[string]corev1.ResourceList{
+		"app": {corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	annotation, err := buildLastAdjustmentAnnotation(pod, newLimits, StrategyFit, "node headroom recalculated (buffer=256Mi)", now)
+	if err != nil {
+		t.Fatalf("buildLastAdjustmentAnnotation() error = %v", err)
+	}
+
+	pod.Annotations = map[string]string{AnnotationLastAdjustment: annotation}
+	rec, err := parseLastAdjustmentRecord(pod)
+	if err != nil {
+		t.Fatalf("parseLastAdjustmentRecord() error = %v", err)
+	}
+
+	if rec.Strategy != StrategyFit {
+		t.Errorf("Strategy = %s, want %s", rec.Strategy, StrategyFit)
+	}
+	if !rec.Timestamp.Equal(now) {
+		t.Errorf("Timestamp = %v, want %v", rec.Timestamp, now)
+	}
+	adjustment, ok := rec.Containers["app"]
+	if !ok {
+		t.Fatalf("Containers[%q] missing, want an entry", "app")
+	}
+	if got := adjustment.Old[corev1.ResourceMemory]; got.String() != "256Mi" {
+		t.Errorf("Old memory = %v, want 256Mi", got)
+	}
+	if got := adjustment.New[corev1.ResourceMemory]; got.String() != "512Mi" {
+		t.Errorf("New memory = %v, want 512Mi", got)
+	}
+}
+
+func TestBuildLastAdjustmentAnnotationOnlyRecordsAdjustedContainers(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app"},
+				{Name: "sidecar"},
+			},
+		},
+	}
+	newLimits := map[string]corev1.ResourceList{
+		"app": {corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+
+	annotation, err := buildLastAdjustmentAnnotation(pod, newLimits, StrategyCap, "capped to container requests, no burst allowed", time.Now())
+	if err != nil {
+		t.Fatalf("buildLastAdjustmentAnnotation() error = %v", err)
+	}
+
+	pod.Annotations = map[string]string{AnnotationLastAdjustment: annotation}
+	rec, err := parseLastAdjustmentRecord(pod)
+	if err != nil {
+		t.Fatalf("parseLastAdjustmentRecord() error = %v", err)
+	}
+	if len(rec.Containers) != 1 {
+		t.Fatalf("Containers = %v, want exactly one entry for the adjusted container", rec.Containers)
+	}
+	if _, ok := rec.Containers["sidecar"]; ok {
+		t.Errorf("Containers has an entry for sidecar, which wasn't adjusted")
+	}
+}
+
+func TestParseLastAdjustmentRecordErrorsWithoutAnnotation(t *testing.T) {
+	pod := &corev1.Pod{}
+	if _, err := parseLastAdjustmentRecord(pod); err == nil {
+		t.Fatal("parseLastAdjustmentRecord() error = nil, want an error when the annotation is missing")
+	}
+}
+
+func TestAdjustmentReasonExplainsTheDrivingKnobPerStrategy(t *testing.T) {
+	tests := []struct {
+		name   string
+		config policy.Config
+		want   string
+	}{
+		{
+			name:   "percent names the percentage",
+			config: policy.Config{Strategy: StrategyPercent, Percent: 80},
+			want:   "80%",
+		},
+		{
+			name:   "fit names the buffer",
+			config: policy.Config{Strategy: StrategyFit, Buffer: resource.MustParse("256Mi")},
+			want:   "256Mi",
+		},
+		{
+			name:   "cap explains there is no burst",
+			config: policy.Config{Strategy: StrategyCap},
+			want:   "no burst",
+		},
+		{
+			name:   "colocation names the high watermark and reclaim ratio",
+			config: policy.Config{Strategy: StrategyColocation, HighWatermark: 90, ReclaimRatio: 50},
+			want:   "90%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := adjustmentReason(tt.config)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("adjustmentReason() = %q, want it to mention %q", got, tt.want)
+			}
+			if got == string(tt.config.Strategy) {
+				t.Errorf("adjustmentReason() = %q, want more than just the strategy name", got)
+			}
+		})
+	}
+}
+
+func TestRecordAdjustedEventDistinguishesCapFromOtherStrategies(t *testing.T) {
+	pod := &corev1.Pod{}
+
+	capRecorder := record.NewFakeRecorder(1)
+	recordAdjustedEvent(capRecorder, pod, StrategyCap)
+	if got := <-capRecorder.Events; !strings.Contains(got, "Capped") {
+		t.Errorf("event = %q, want it to use reason Capped for the cap strategy", got)
+	}
+
+	fitRecorder := record.NewFakeRecorder(1)
+	recordAdjustedEvent(fitRecorder, pod, StrategyFit)
+	if got := <-fitRecorder.Events; !strings.Contains(got, "Adjusted") {
+		t.Errorf("event = %q, want it to use reason Adjusted for non-cap strategies", got)
+	}
+}
+
+func TestRecordEventHelpersToleratesNilRecorder(t *testing.T) {
+	pod := &corev1.Pod{}
+	// None of these should panic when Recorder is nil, the zero value for a
+	// PodReconciler that hasn't been wired with an EventRecorder.
+	recordAdjustedEvent(nil, pod, StrategyFit)
+	recordFailedResizeEvent(nil, pod, nil)
+	recordResizeUnsupportedEvent(nil, pod)
+	recordResizeRejectedEvent(nil, pod, string(corev1.PodResizeStatusInfeasible))
+}
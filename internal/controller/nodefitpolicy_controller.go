@@ -0,0 +1,136 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodefitv1alpha1 "github.com/KamorionLabs/node-fit-controller/api/v1alpha1"
+)
+
+const conditionAccepted = "Accepted"
+
+// validatePolicyRule checks the parts of a PolicyRule that the CRD's OpenAPI
+// schema cannot express, such as whether the selectors actually compile.
+func validatePolicyRule(rule nodefitv1alpha1.PolicyRule) metav1.Condition {
+	if rule.WorkloadSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(rule.WorkloadSelector); err != nil {
+			return metav1.Condition{
+				Type:    conditionAccepted,
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidWorkloadSelector",
+				Message: err.Error(),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionAccepted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Valid",
+		Message: "policy accepted",
+	}
+}
+
+// NodeFitPolicyReconciler validates cluster-scoped NodeFitPolicy objects and
+// reports the result on Status.Conditions. PolicyIndex merges the policies
+// at read time, so this reconciler's only job is feedback for operators.
+type NodeFitPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=nodefit.io,resources=nodefitpolicies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=nodefit.io,resources=nodefitpolicies/status,verbs=get;update;patch
+
+func (r *NodeFitPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var policy nodefitv1alpha1.NodeFitPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	condition := validatePolicyRule(policy.Spec.PolicyRule)
+	if policy.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector); err != nil {
+			condition = metav1.Condition{
+				Type:    conditionAccepted,
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidNamespaceSelector",
+				Message: err.Error(),
+			}
+		}
+	}
+	condition.ObservedGeneration = policy.Generation
+
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+	policy.Status.ObservedGeneration = policy.Generation
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeFitPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nodefitv1alpha1.NodeFitPolicy{}).
+		Named("nodefitpolicy").
+		Complete(r)
+}
+
+// NamespaceNodeFitPolicyReconciler validates namespace-scoped
+// NamespaceNodeFitPolicy objects the same way NodeFitPolicyReconciler does
+// for the cluster-scoped kind.
+type NamespaceNodeFitPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=nodefit.io,resources=namespacenodefitpolicies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=nodefit.io,resources=namespacenodefitpolicies/status,verbs=get;update;patch
+
+func (r *NamespaceNodeFitPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var policy nodefitv1alpha1.NamespaceNodeFitPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	condition := validatePolicyRule(policy.Spec.PolicyRule)
+	condition.ObservedGeneration = policy.Generation
+
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+	policy.Status.ObservedGeneration = policy.Generation
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceNodeFitPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nodefitv1alpha1.NamespaceNodeFitPolicy{}).
+		Named("namespacenodefitpolicy").
+		Complete(r)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeResolver abstracts how CalculateLimits obtains the Node to calculate
+// a pod's limits against. PodReconciler already has a live Node fetched by
+// the time it calculates (the pod is Running and scheduled); the admission
+// webhook instead has to predict one before the pod has even been
+// persisted. Both sides feed the same strategy code through this interface.
+type NodeResolver interface {
+	// ResolveNode returns the Node to calculate against. ok is false when
+	// the target Node cannot be determined yet, in which case callers
+	// should skip the calculation rather than guess.
+	ResolveNode(ctx context.Context) (node *corev1.Node, ok bool, err error)
+}
+
+// FixedNodeResolver resolves to a Node the caller already has in hand.
+type FixedNodeResolver struct {
+	node *corev1.Node
+}
+
+// NewFixedNodeResolver returns a NodeResolver that always resolves to node.
+func NewFixedNodeResolver(node *corev1.Node) *FixedNodeResolver {
+	return &FixedNodeResolver{node: node}
+}
+
+// ResolveNode implements NodeResolver.
+func (f *FixedNodeResolver) ResolveNode(context.Context) (*corev1.Node, bool, error) {
+	return f.node, f.node != nil, nil
+}
@@ -18,50 +18,95 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nodefitv1alpha1 "github.com/KamorionLabs/node-fit-controller/api/v1alpha1"
+	nodefitmetrics "github.com/KamorionLabs/node-fit-controller/internal/metrics"
+	"github.com/KamorionLabs/node-fit-controller/internal/policy"
 )
 
 const (
 	// Annotations for opt-in and configuration
-	AnnotationEnabled  = "nodefit.io/enabled"
-	AnnotationStrategy = "nodefit.io/strategy"
-	AnnotationPercent  = "nodefit.io/percent"  // For percent strategy
-	AnnotationBuffer   = "nodefit.io/buffer"   // For fit strategy (e.g., "256Mi")
-	AnnotationAdjusted = "nodefit.io/adjusted" // Marker that limits were adjusted
+	AnnotationEnabled       = "nodefit.io/enabled"
+	AnnotationStrategy      = "nodefit.io/strategy"
+	AnnotationPercent       = "nodefit.io/percent"        // For percent strategy
+	AnnotationBuffer        = "nodefit.io/buffer"         // For fit and colocation strategies (e.g., "256Mi")
+	AnnotationHighWatermark = "nodefit.io/high-watermark" // For colocation strategy
+	AnnotationReclaimRatio  = "nodefit.io/reclaim-ratio"  // For colocation strategy
+	AnnotationDegradePolicy = "nodefit.io/degrade-policy" // For colocation strategy
+	AnnotationAdjusted      = "nodefit.io/adjusted"       // Marker that limits were adjusted
+	AnnotationPendingFit    = "nodefit.io/pending-fit"    // Set by the mutating webhook when it couldn't predict the Node
 
 	// Strategies
-	StrategyPercent = "percent"  // limit = min(original, percent% of node_allocatable / pods_count)
-	StrategyFit     = "fit"      // limit = node_allocatable - sum(other_pods_requests) - buffer
-	StrategyCap     = "cap"      // limit = request (no burst allowed)
+	StrategyPercent    = "percent"    // limit = min(original, percent% of node_allocatable / pods_count)
+	StrategyFit        = "fit"        // limit = node_allocatable - sum(other_pods_requests) - buffer
+	StrategyCap        = "cap"        // limit = request (no burst allowed)
+	StrategyColocation = "colocation" // limit = allocatable*highWatermark - actual_usage - buffer, scaled by reclaimRatio
 
 	// Defaults
-	DefaultStrategy = StrategyPercent
-	DefaultPercent  = 80
-	DefaultBuffer   = "256Mi"
+	DefaultStrategy      = StrategyPercent
+	DefaultPercent       = 80
+	DefaultBuffer        = "256Mi"
+	DefaultHighWatermark = 90
+	DefaultReclaimRatio  = 100
+	DefaultDegradePolicy = StrategyFit
 )
 
-// PodReconciler reconciles Pods with nodefit.io annotations
+// PodReconciler reconciles Pods selected by a NodeFitPolicy, or, for
+// backward compatibility, carrying nodefit.io annotations.
 type PodReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// PolicyIndex resolves the effective Config for a pod from NodeFitPolicy
+	// and NamespaceNodeFitPolicy objects. It takes precedence over
+	// annotation-based configuration; nil disables CRD-based selection so
+	// existing annotation-only deployments keep working untouched.
+	PolicyIndex *policy.Index
+
+	// MetricsProvider supplies live node usage for the colocation strategy.
+	// nil makes calculateColocationLimits degrade to config.DegradePolicy
+	// unconditionally.
+	MetricsProvider nodefitmetrics.NodeMetricsProvider
+
+	// Recorder emits the Skipped event when a pod contains a BestEffort
+	// container nodefit cannot compute a proportional limit for.
+	Recorder record.EventRecorder
+
+	// ResizeGate decides whether the pods/resize subresource is safe to
+	// use on the target cluster; nil degrades patchPodLimits to emitting a
+	// warning event instead of risking a rejected patch or a surprise
+	// container restart on clusters older than 1.27.
+	ResizeGate *ResizeFeatureGate
 }
 
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nodefit.io,resources=nodefitpolicies;namespacenodefitpolicies,verbs=get;list;watch
 
 // Reconcile handles pod reconciliation
 func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
+	start := time.Now()
+	defer func() {
+		nodefitmetrics.ReconcileDuration.Observe(time.Since(start).Seconds())
+	}()
 
 	// Fetch the Pod
 	var pod corev1.Pod
@@ -69,9 +114,27 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Check if nodefit is enabled via annotation
-	if !isNodeFitEnabled(&pod) {
-		return ctrl.Result{}, nil
+	// A resize this controller issued earlier may be rejected by the
+	// kubelet after patchPodLimits already gave up waiting for it; the
+	// resize subresource wrote the desired values into Spec regardless, so
+	// needsUpdate below would never notice. Catch a late Infeasible/Deferred
+	// here, independent of whether a new limit needs computing at all.
+	if result, handled, err := r.handleRejectedResize(ctx, &pod); err != nil {
+		logger.Error(err, "Failed to handle a previously rejected resize")
+		return ctrl.Result{}, err
+	} else if handled {
+		return result, nil
+	}
+
+	// Resolve the effective configuration, preferring a matching
+	// NodeFitPolicy/NamespaceNodeFitPolicy over the legacy annotations.
+	config, err := r.ResolveConfig(ctx, &pod)
+	if err != nil {
+		if err == ErrNoConfig {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to resolve nodefit configuration")
+		return ctrl.Result{}, err
 	}
 
 	// Skip if pod is not running or not scheduled
@@ -92,44 +155,84 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, err
 	}
 
-	// Get configuration from annotations
-	config := getConfig(&pod)
 	logger.Info("Processing pod", "pod", req.NamespacedName, "node", pod.Spec.NodeName, "strategy", config.Strategy)
+	strategy := string(config.Strategy)
+
+	// A BestEffort container has no request to distribute a proportional
+	// limit against, so skip the whole pod rather than guess.
+	if PodHasBestEffortContainer(&pod, config.ContainerNames) {
+		logger.Info("Pod has a BestEffort container, skipping", "pod", req.NamespacedName)
+		nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultSkipped).Inc()
+		if r.Recorder != nil {
+			r.Recorder.Event(&pod, corev1.EventTypeWarning, "Skipped", "nodefit: pod has a BestEffort container, cannot compute a proportional limit")
+		}
+		return ctrl.Result{}, nil
+	}
 
-	// Calculate new limits based on strategy
-	newLimits, err := r.calculateLimits(ctx, &pod, &node, config)
+	// Calculate the pod-level limit ceiling based on strategy, then split it
+	// across the selected containers in proportion to their requests. The
+	// Node is already in hand, so wrap it in a FixedNodeResolver; the
+	// admission webhook instead predicts one before the pod is scheduled.
+	totalLimits, ok, err := r.CalculateLimits(ctx, &pod, NewFixedNodeResolver(&node), config)
 	if err != nil {
 		logger.Error(err, "Failed to calculate limits")
+		nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultError).Inc()
 		return ctrl.Result{}, err
 	}
+	if !ok {
+		// Can't happen: the Node above was just fetched successfully.
+		return ctrl.Result{}, nil
+	}
+	for resourceName, quantity := range totalLimits {
+		nodefitmetrics.PodLimitBytes.WithLabelValues(req.String(), string(resourceName), strategy).Set(float64(scaledValue(quantity, resourceName)))
+	}
+	if available, err := r.nodeAvailableBytes(ctx, &node, config.Resources); err != nil {
+		logger.Error(err, "Failed to compute node headroom for metrics", "node", node.Name)
+	} else {
+		for resourceName, quantity := range available {
+			nodefitmetrics.NodeAvailableBytes.WithLabelValues(node.Name, string(resourceName)).Set(float64(scaledValue(quantity, resourceName)))
+		}
+	}
+	newLimits := DistributeLimitsAcrossContainers(&pod, totalLimits, config.ContainerNames)
 
 	// Check if we need to update
 	if !needsUpdate(&pod, newLimits) {
 		logger.V(1).Info("No update needed", "pod", req.NamespacedName)
+		nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultNoop).Inc()
 		return ctrl.Result{}, nil
 	}
 
-	// Patch the pod with new limits (in-place resize - K8s 1.35+)
-	if err := r.patchPodLimits(ctx, &pod, newLimits); err != nil {
+	// Patch the pod with new limits, preferring the in-place resize
+	// subresource over a plain spec patch where the cluster and the
+	// containers' ResizePolicy allow it.
+	result, err := r.patchPodLimits(ctx, &pod, newLimits, config)
+	if err != nil {
 		logger.Error(err, "Failed to patch pod limits")
+		nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultError).Inc()
 		return ctrl.Result{}, err
 	}
+	if result.RequeueAfter > 0 {
+		nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultSkipped).Inc()
+		return result, nil
+	}
+	nodefitmetrics.AdjustmentsTotal.WithLabelValues(strategy, nodefitmetrics.ResultAdjusted).Inc()
 
 	logger.Info("Successfully adjusted pod limits",
 		"pod", req.NamespacedName,
 		"strategy", config.Strategy,
-		"newMemoryLimit", newLimits.Memory().String(),
-		"newCPULimit", newLimits.Cpu().String())
+		"newMemoryLimit", totalLimits.Memory().String(),
+		"newCPULimit", totalLimits.Cpu().String())
 
 	return ctrl.Result{}, nil
 }
 
-// Config holds the nodefit configuration from annotations
-type Config struct {
-	Strategy string
-	Percent  int
-	Buffer   resource.Quantity
-}
+// Config is an alias for policy.Config so existing call sites in this
+// package (and anyone vendoring it) keep compiling unchanged.
+type Config = policy.Config
+
+// ErrNoConfig is returned by ResolveConfig when a pod is neither selected by
+// a NodeFitPolicy/NamespaceNodeFitPolicy nor opted in via annotation.
+var ErrNoConfig = errors.New("nodefit: pod is not selected by any policy or annotation")
 
 func isNodeFitEnabled(pod *corev1.Pod) bool {
 	if pod.Annotations == nil {
@@ -139,11 +242,38 @@ func isNodeFitEnabled(pod *corev1.Pod) bool {
 	return ok && strings.ToLower(enabled) == "true"
 }
 
-func getConfig(pod *corev1.Pod) Config {
+// ResolveConfig determines the effective Config for pod. A matching
+// NodeFitPolicy or NamespaceNodeFitPolicy always takes precedence; when none
+// matches, it falls back to the legacy nodefit.io/* annotations so existing
+// annotation-only deployments are unaffected.
+func (r *PodReconciler) ResolveConfig(ctx context.Context, pod *corev1.Pod) (Config, error) {
+	if r.PolicyIndex != nil {
+		config, err := r.PolicyIndex.Resolve(ctx, pod)
+		switch {
+		case err == nil:
+			return config, nil
+		case err != policy.ErrNoPolicy:
+			return Config{}, err
+		}
+	}
+
+	if !isNodeFitEnabled(pod) {
+		return Config{}, ErrNoConfig
+	}
+	return getAnnotationConfig(pod), nil
+}
+
+// getAnnotationConfig holds the nodefit configuration sourced from the
+// legacy per-pod nodefit.io/* annotations.
+func getAnnotationConfig(pod *corev1.Pod) Config {
 	config := Config{
-		Strategy: DefaultStrategy,
-		Percent:  DefaultPercent,
-		Buffer:   resource.MustParse(DefaultBuffer),
+		Strategy:      DefaultStrategy,
+		Percent:       DefaultPercent,
+		Buffer:        resource.MustParse(DefaultBuffer),
+		Resources:     []corev1.ResourceName{corev1.ResourceMemory, corev1.ResourceCPU},
+		HighWatermark: DefaultHighWatermark,
+		ReclaimRatio:  DefaultReclaimRatio,
+		DegradePolicy: DefaultDegradePolicy,
 	}
 
 	if pod.Annotations == nil {
@@ -151,9 +281,9 @@ func getConfig(pod *corev1.Pod) Config {
 	}
 
 	if strategy, ok := pod.Annotations[AnnotationStrategy]; ok {
-		switch strings.ToLower(strategy) {
-		case StrategyPercent, StrategyFit, StrategyCap:
-			config.Strategy = strings.ToLower(strategy)
+		switch nodefitv1alpha1.Strategy(strings.ToLower(strategy)) {
+		case StrategyPercent, StrategyFit, StrategyCap, StrategyColocation:
+			config.Strategy = nodefitv1alpha1.Strategy(strings.ToLower(strategy))
 		}
 	}
 
@@ -169,24 +299,62 @@ func getConfig(pod *corev1.Pod) Config {
 		}
 	}
 
+	if hwStr, ok := pod.Annotations[AnnotationHighWatermark]; ok {
+		if hw, err := strconv.Atoi(hwStr); err == nil && hw > 0 && hw <= 100 {
+			config.HighWatermark = hw
+		}
+	}
+
+	if rrStr, ok := pod.Annotations[AnnotationReclaimRatio]; ok {
+		if rr, err := strconv.Atoi(rrStr); err == nil && rr > 0 && rr <= 100 {
+			config.ReclaimRatio = rr
+		}
+	}
+
+	if degradeStr, ok := pod.Annotations[AnnotationDegradePolicy]; ok {
+		switch nodefitv1alpha1.Strategy(strings.ToLower(degradeStr)) {
+		case StrategyFit, StrategyCap:
+			config.DegradePolicy = nodefitv1alpha1.Strategy(strings.ToLower(degradeStr))
+		}
+	}
+
 	return config
 }
 
-func (r *PodReconciler) calculateLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, config Config) (corev1.ResourceList, error) {
+// CalculateLimits resolves the target Node via resolver and computes the
+// pod-level limit ceiling for config.Strategy. ok is false, with a nil
+// error, when resolver cannot determine the Node yet (e.g. the admission
+// webhook saw a pod the scheduler hasn't narrowed down to one candidate),
+// in which case callers should not attempt a calculation at all.
+//
+// resolver abstracts "get node" (PodReconciler, which already knows the
+// Node a Running pod landed on) from "predicted node" (the admission
+// webhook, which has to guess before the pod exists) so this strategy
+// dispatch serves both without caring which one it's talking to.
+func (r *PodReconciler) CalculateLimits(ctx context.Context, pod *corev1.Pod, resolver NodeResolver, config Config) (corev1.ResourceList, bool, error) {
+	node, ok, err := resolver.ResolveNode(ctx)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	var limits corev1.ResourceList
 	switch config.Strategy {
 	case StrategyPercent:
-		return r.calculatePercentLimits(ctx, pod, node, config.Percent)
+		limits, err = r.calculatePercentLimits(ctx, pod, node, config)
 	case StrategyFit:
-		return r.calculateFitLimits(ctx, pod, node, config.Buffer)
+		limits, err = r.calculateFitLimits(ctx, pod, node, config)
 	case StrategyCap:
-		return r.calculateCapLimits(pod)
+		limits, err = r.calculateCapLimits(pod, config)
+	case StrategyColocation:
+		limits, err = r.calculateColocationLimits(ctx, pod, node, config)
 	default:
-		return nil, fmt.Errorf("unknown strategy: %s", config.Strategy)
+		err = fmt.Errorf("unknown strategy: %s", config.Strategy)
 	}
+	return limits, true, err
 }
 
 // calculatePercentLimits: limit = min(original_limit, percent% of node_allocatable / pods_on_node)
-func (r *PodReconciler) calculatePercentLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, percent int) (corev1.ResourceList, error) {
+func (r *PodReconciler) calculatePercentLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, config Config) (corev1.ResourceList, error) {
 	newLimits := make(corev1.ResourceList)
 
 	// Count pods on this node
@@ -199,48 +367,40 @@ func (r *PodReconciler) calculatePercentLimits(ctx context.Context, pod *corev1.
 	}
 
 	allocatable := node.Status.Allocatable
+	currentLimits := podCurrentLimits(pod, config.ContainerNames)
 
-	// Calculate memory limit
-	if allocatableMem, ok := allocatable[corev1.ResourceMemory]; ok {
-		// (allocatable * percent / 100) / podCount
-		maxMemBytes := allocatableMem.Value() * int64(percent) / 100 / int64(podCount)
-		maxMem := resource.NewQuantity(maxMemBytes, resource.BinarySI)
-
-		// Get current limit
-		currentLimit := getCurrentLimit(pod, corev1.ResourceMemory)
-		if currentLimit != nil && currentLimit.Value() > 0 {
-			// Use minimum of current limit and calculated max
-			if maxMem.Value() < currentLimit.Value() {
-				newLimits[corev1.ResourceMemory] = *maxMem
-			} else {
-				newLimits[corev1.ResourceMemory] = *currentLimit
-			}
-		} else {
-			newLimits[corev1.ResourceMemory] = *maxMem
+	for _, resourceName := range config.Resources {
+		allocatableQuantity, ok := allocatable[resourceName]
+		if !ok {
+			continue
 		}
-	}
 
-	// Calculate CPU limit (similar logic)
-	if allocatableCPU, ok := allocatable[corev1.ResourceCPU]; ok {
-		maxCPUMilli := allocatableCPU.MilliValue() * int64(percent) / 100 / int64(podCount)
-		maxCPU := resource.NewMilliQuantity(maxCPUMilli, resource.DecimalSI)
+		// (allocatable * percent / 100) / podCount
+		maxScaled := scaledValue(allocatableQuantity, resourceName) * int64(config.Percent) / 100 / int64(podCount)
 
-		currentLimit := getCurrentLimit(pod, corev1.ResourceCPU)
-		if currentLimit != nil && currentLimit.MilliValue() > 0 {
-			if maxCPU.MilliValue() < currentLimit.MilliValue() {
-				newLimits[corev1.ResourceCPU] = *maxCPU
-			} else {
-				newLimits[corev1.ResourceCPU] = *currentLimit
+		currentLimit, hasCurrentLimit := currentLimits[resourceName]
+		if !hasCurrentLimit || scaledValue(currentLimit, resourceName) == 0 {
+			// CPU historically keeps burstable (no limit) when none was set.
+			if resourceName == corev1.ResourceCPU {
+				continue
 			}
+			newLimits[resourceName] = quantityFromScaled(maxScaled, resourceName)
+			continue
+		}
+
+		// Use the minimum of the current limit and the calculated max.
+		if currentScaled := scaledValue(currentLimit, resourceName); maxScaled < currentScaled {
+			newLimits[resourceName] = quantityFromScaled(maxScaled, resourceName)
+		} else {
+			newLimits[resourceName] = currentLimit
 		}
-		// Don't set CPU limit if not already set (best practice)
 	}
 
 	return newLimits, nil
 }
 
 // calculateFitLimits: limit = node_allocatable - sum(other_pods_requests) - buffer
-func (r *PodReconciler) calculateFitLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, buffer resource.Quantity) (corev1.ResourceList, error) {
+func (r *PodReconciler) calculateFitLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, config Config) (corev1.ResourceList, error) {
 	newLimits := make(corev1.ResourceList)
 
 	// Get all pods on this node
@@ -249,9 +409,8 @@ func (r *PodReconciler) calculateFitLimits(ctx context.Context, pod *corev1.Pod,
 		return newLimits, err
 	}
 
-	// Sum requests of other pods
-	var otherPodsMemRequests int64
-	var otherPodsCPURequests int64
+	// Sum effective (sidecar/init-aware) requests of other pods
+	otherPodsRequests := corev1.ResourceList{}
 	for _, p := range podList.Items {
 		if p.UID == pod.UID {
 			continue // Skip our own pod
@@ -259,52 +418,142 @@ func (r *PodReconciler) calculateFitLimits(ctx context.Context, pod *corev1.Pod,
 		if p.Status.Phase != corev1.PodRunning && p.Status.Phase != corev1.PodPending {
 			continue
 		}
-		for _, container := range p.Spec.Containers {
-			if mem, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-				otherPodsMemRequests += mem.Value()
-			}
-			if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-				otherPodsCPURequests += cpu.MilliValue()
-			}
-		}
+		addResourceList(otherPodsRequests, podEffectiveRequests(&p))
 	}
 
 	allocatable := node.Status.Allocatable
 
-	// Calculate available memory
-	if allocatableMem, ok := allocatable[corev1.ResourceMemory]; ok {
-		availableMem := allocatableMem.Value() - otherPodsMemRequests - buffer.Value()
-		if availableMem < 0 {
-			availableMem = 0
+	for _, resourceName := range config.Resources {
+		allocatableQuantity, ok := allocatable[resourceName]
+		if !ok {
+			continue
 		}
-		newLimits[corev1.ResourceMemory] = *resource.NewQuantity(availableMem, resource.BinarySI)
-	}
 
-	// Calculate available CPU
-	if allocatableCPU, ok := allocatable[corev1.ResourceCPU]; ok {
-		availableCPU := allocatableCPU.MilliValue() - otherPodsCPURequests
-		if availableCPU < 0 {
-			availableCPU = 0
+		available := scaledValue(allocatableQuantity, resourceName)
+		if other, ok := otherPodsRequests[resourceName]; ok {
+			available -= scaledValue(other, resourceName)
+		}
+		if resourceName == corev1.ResourceMemory {
+			available -= scaledValue(config.Buffer, resourceName)
+		}
+		if available < 0 {
+			available = 0
 		}
-		newLimits[corev1.ResourceCPU] = *resource.NewMilliQuantity(availableCPU, resource.DecimalSI)
+		newLimits[resourceName] = quantityFromScaled(available, resourceName)
 	}
 
 	return newLimits, nil
 }
 
-// calculateCapLimits: limit = request (no burst)
-func (r *PodReconciler) calculateCapLimits(pod *corev1.Pod) (corev1.ResourceList, error) {
+// nodeAvailableBytes computes node-wide resource headroom — allocatable
+// minus the summed effective requests of every pod currently on the node —
+// independent of whichever pod is being reconciled or which strategy it
+// uses. This is what NodeAvailableBytes reports; it is deliberately not the
+// per-pod limit computed above, which a percent/cap strategy or a pod's own
+// buffer would otherwise leak into a metric operators read as node headroom.
+func (r *PodReconciler) nodeAvailableBytes(ctx context.Context, node *corev1.Node, resources []corev1.ResourceName) (corev1.ResourceList, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+		return nil, err
+	}
+
+	used := corev1.ResourceList{}
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		if p.Status.Phase != corev1.PodRunning && p.Status.Phase != corev1.PodPending {
+			continue
+		}
+		addResourceList(used, podEffectiveRequests(p))
+	}
+
+	allocatable := node.Status.Allocatable
+	available := make(corev1.ResourceList, len(resources))
+	for _, resourceName := range resources {
+		allocatableQuantity, ok := allocatable[resourceName]
+		if !ok {
+			continue
+		}
+		headroom := scaledValue(allocatableQuantity, resourceName)
+		if usedQuantity, ok := used[resourceName]; ok {
+			headroom -= scaledValue(usedQuantity, resourceName)
+		}
+		if headroom < 0 {
+			headroom = 0
+		}
+		available[resourceName] = quantityFromScaled(headroom, resourceName)
+	}
+	return available, nil
+}
+
+// calculateColocationLimits: available = allocatable*highWatermark - actualUsage - buffer,
+// scaled by reclaimRatio. Falls back to config.DegradePolicy when no live
+// usage sample is available within the metrics provider's TTL.
+func (r *PodReconciler) calculateColocationLimits(ctx context.Context, pod *corev1.Pod, node *corev1.Node, config Config) (corev1.ResourceList, error) {
+	logger := log.FromContext(ctx)
+
+	if r.MetricsProvider == nil {
+		logger.V(1).Info("No metrics provider configured, degrading", "node", node.Name, "degradePolicy", config.DegradePolicy)
+		return r.degradeColocation(ctx, pod, node, config)
+	}
+
+	usage, err := r.MetricsProvider.NodeUsage(ctx, node.Name)
+	if err != nil {
+		logger.Info("Node usage unavailable, degrading", "node", node.Name, "reason", err, "degradePolicy", config.DegradePolicy)
+		return r.degradeColocation(ctx, pod, node, config)
+	}
+
 	newLimits := make(corev1.ResourceList)
+	allocatable := node.Status.Allocatable
+
+	for _, resourceName := range config.Resources {
+		allocatableQuantity, ok := allocatable[resourceName]
+		if !ok {
+			continue
+		}
 
-	for _, container := range pod.Spec.Containers {
-		if mem, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-			newLimits[corev1.ResourceMemory] = mem
+		ceiling := scaledValue(allocatableQuantity, resourceName) * int64(config.HighWatermark) / 100
+		available := ceiling
+		if used, ok := usage[resourceName]; ok {
+			available -= scaledValue(used, resourceName)
+		}
+		if resourceName == corev1.ResourceMemory {
+			available -= scaledValue(config.Buffer, resourceName)
+		}
+		available = available * int64(config.ReclaimRatio) / 100
+		if available < 0 {
+			available = 0
 		}
-		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-			newLimits[corev1.ResourceCPU] = cpu
+		newLimits[resourceName] = quantityFromScaled(available, resourceName)
+	}
+
+	return newLimits, nil
+}
+
+// degradeColocation runs config.DegradePolicy in place of the colocation
+// strategy when live node usage cannot be trusted.
+func (r *PodReconciler) degradeColocation(ctx context.Context, pod *corev1.Pod, node *corev1.Node, config Config) (corev1.ResourceList, error) {
+	switch config.DegradePolicy {
+	case StrategyCap:
+		return r.calculateCapLimits(pod, config)
+	default:
+		return r.calculateFitLimits(ctx, pod, node, config)
+	}
+}
+
+// calculateCapLimits: limit = request (no burst). Returns the pod-level
+// effective requests (sidecar- and init-container-aware, over all
+// containers); DistributeLimitsAcrossContainers then splits that total in
+// proportion to the selected containers' requests. That hands each container
+// back exactly its own request only when the total equals the selected
+// containers' summed requests; sidecar requests, a larger init-container
+// peak, or a ContainerNames filter raise each share above its request.
+func (r *PodReconciler) calculateCapLimits(pod *corev1.Pod, config Config) (corev1.ResourceList, error) {
+	newLimits := make(corev1.ResourceList)
+	effective := podEffectiveRequests(pod)
+	for _, resourceName := range config.Resources {
+		if req, ok := effective[resourceName]; ok {
+			newLimits[resourceName] = req
 		}
-		// Only process first container for now
-		break
 	}
 
 	return newLimits, nil
@@ -325,22 +574,20 @@ func (r *PodReconciler) countPodsOnNode(ctx context.Context, nodeName string) (i
 	return count, nil
 }
 
-func getCurrentLimit(pod *corev1.Pod, resourceName corev1.ResourceName) *resource.Quantity {
-	for _, container := range pod.Spec.Containers {
-		if limit, ok := container.Resources.Limits[resourceName]; ok {
-			return &limit
-		}
-	}
-	return nil
-}
-
-func needsUpdate(pod *corev1.Pod, newLimits corev1.ResourceList) bool {
+// needsUpdate reports whether any container's limits in newLimits (keyed by
+// container name) differ from what the pod already has.
+func needsUpdate(pod *corev1.Pod, newLimits map[string]corev1.ResourceList) bool {
 	if len(newLimits) == 0 {
 		return false
 	}
 
-	for _, container := range pod.Spec.Containers {
-		for resourceName, newLimit := range newLimits {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		limits, ok := newLimits[container.Name]
+		if !ok {
+			continue
+		}
+		for resourceName, newLimit := range limits {
 			currentLimit, ok := container.Resources.Limits[resourceName]
 			if !ok || !currentLimit.Equal(newLimit) {
 				return true
@@ -350,28 +597,6 @@ func needsUpdate(pod *corev1.Pod, newLimits corev1.ResourceList) bool {
 	return false
 }
 
-func (r *PodReconciler) patchPodLimits(ctx context.Context, pod *corev1.Pod, newLimits corev1.ResourceList) error {
-	patch := client.MergeFrom(pod.DeepCopy())
-
-	// Update limits for all containers
-	for i := range pod.Spec.Containers {
-		if pod.Spec.Containers[i].Resources.Limits == nil {
-			pod.Spec.Containers[i].Resources.Limits = make(corev1.ResourceList)
-		}
-		for resourceName, limit := range newLimits {
-			pod.Spec.Containers[i].Resources.Limits[resourceName] = limit
-		}
-	}
-
-	// Add adjusted annotation
-	if pod.Annotations == nil {
-		pod.Annotations = make(map[string]string)
-	}
-	pod.Annotations[AnnotationAdjusted] = "true"
-
-	return r.Patch(ctx, pod, patch)
-}
-
 // SetupWithManager sets up the controller with the Manager.
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Create an index for pods by node name
@@ -384,6 +609,30 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).
+		Watches(&nodefitv1alpha1.NodeFitPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapPolicyToPodRequests)).
+		Watches(&nodefitv1alpha1.NamespaceNodeFitPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapPolicyToPodRequests)).
 		Named("nodefit-pod").
 		Complete(r)
 }
+
+// mapPolicyToPodRequests re-enqueues every pod a changed NodeFitPolicy or
+// NamespaceNodeFitPolicy selects, so editing a policy's percent, priority,
+// or selectors takes effect on already-running pods instead of waiting for
+// some unrelated pod event to fire.
+func (r *PodReconciler) mapPolicyToPodRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.PolicyIndex == nil {
+		return nil
+	}
+
+	keys, err := r.PolicyIndex.MatchingPods(ctx, obj)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve pods affected by policy change", "policy", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(keys))
+	for _, key := range keys {
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+	return requests
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nodefitv1alpha1 "github.com/KamorionLabs/node-fit-controller/api/v1alpha1"
+	"github.com/KamorionLabs/node-fit-controller/internal/policy"
+)
+
+func nodeIndexFunc(obj client.Object) []string {
+	return []string{obj.(*corev1.Pod).Spec.NodeName}
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *PodReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme: %v", err)
+	}
+	if err := nodefitv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("nodefitv1alpha1.AddToScheme: %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", nodeIndexFunc).
+		WithObjects(objs...).
+		Build()
+	return &PodReconciler{Client: c, PolicyIndex: policy.NewIndex(c)}
+}
+
+func nodeWithAllocatable(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func podOnNode(namespace, name, nodeName string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		// UID must be distinct (and non-empty) across test pods: calculateFitLimits
+		// uses it to tell a pod apart from itself in the node's pod list, and the
+		// fake client doesn't assign one.
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestResolveConfigPrefersPolicyOverAnnotations(t *testing.T) {
+	policyObj := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyFit},
+		},
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	r := newTestReconciler(t, ns, policyObj)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "app",
+		Annotations: map[string]string{AnnotationEnabled: "true", AnnotationStrategy: StrategyCap},
+	}}
+
+	config, err := r.ResolveConfig(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if config.Strategy != nodefitv1alpha1.StrategyFit {
+		t.Errorf("Strategy = %s, want %s (policy should win over the annotation)", config.Strategy, nodefitv1alpha1.StrategyFit)
+	}
+}
+
+func TestResolveConfigFallsBackToAnnotationsWhenNoPolicyMatches(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	r := newTestReconciler(t, ns)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "app",
+		Annotations: map[string]string{AnnotationEnabled: "true", AnnotationStrategy: StrategyCap},
+	}}
+
+	config, err := r.ResolveConfig(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("ResolveConfig() error = %v", err)
+	}
+	if config.Strategy != StrategyCap {
+		t.Errorf("Strategy = %s, want %s", config.Strategy, StrategyCap)
+	}
+}
+
+func TestResolveConfigReturnsErrNoConfigWhenNeitherMatches(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	r := newTestReconciler(t, ns)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+
+	if _, err := r.ResolveConfig(context.Background(), pod); err != ErrNoConfig {
+		t.Fatalf("ResolveConfig() error = %v, want ErrNoConfig", err)
+	}
+}
+
+func TestCalculatePercentLimits(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "1000Mi")
+
+	tests := []struct {
+		name         string
+		pod          *corev1.Pod
+		otherPods    []client.Object
+		wantMemory   string
+		wantNoCPULim bool
+	}{
+		{
+			name:         "no existing CPU limit keeps CPU burstable",
+			pod:          podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi"),
+			wantNoCPULim: true,
+			wantMemory:   "800Mi", // 80% of 1000Mi / 1 pod
+		},
+		{
+			name: "existing limit below the percent ceiling is kept as-is",
+			pod: func() *corev1.Pod {
+				p := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+				p.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("200Mi"),
+				}
+				return p
+			}(),
+			wantMemory: "200Mi",
+		},
+		{
+			name: "existing limit above the percent ceiling is capped down",
+			pod: func() *corev1.Pod {
+				p := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+				p.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("2000Mi"),
+				}
+				return p
+			}(),
+			wantMemory: "800Mi",
+		},
+		{
+			name:       "two pods on the node halve the per-pod ceiling",
+			pod:        podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi"),
+			otherPods:  []client.Object{podOnNode("default", "other", "n1", corev1.PodRunning, "100m", "100Mi")},
+			wantMemory: "400Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := append([]client.Object{tt.pod}, tt.otherPods...)
+			r := newTestReconciler(t, objs...)
+			config := policy.DefaultConfig()
+
+			got, err := r.calculatePercentLimits(context.Background(), tt.pod, node, config)
+			if err != nil {
+				t.Fatalf("calculatePercentLimits() error = %v", err)
+			}
+
+			if tt.wantNoCPULim {
+				if _, ok := got[corev1.ResourceCPU]; ok {
+					t.Errorf("got CPU limit %v, want none (CPU stays burstable without a prior limit)", got[corev1.ResourceCPU])
+				}
+			}
+			if mem, ok := got[corev1.ResourceMemory]; !ok || mem.String() != tt.wantMemory {
+				t.Errorf("Memory = %v, want %s", got[corev1.ResourceMemory], tt.wantMemory)
+			}
+		})
+	}
+}
+
+func TestCalculateFitLimits(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "1000Mi")
+	pod := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+	other := podOnNode("default", "other", "n1", corev1.PodRunning, "200m", "300Mi")
+	notOnNode := podOnNode("default", "elsewhere", "n2", corev1.PodRunning, "500m", "500Mi")
+
+	r := newTestReconciler(t, pod, other, notOnNode)
+	config := policy.DefaultConfig() // Buffer: 256Mi
+
+	got, err := r.calculateFitLimits(context.Background(), pod, node, config)
+	if err != nil {
+		t.Fatalf("calculateFitLimits() error = %v", err)
+	}
+
+	// memory: 1000Mi allocatable - 300Mi (other pod) - 256Mi buffer = 444Mi
+	if mem := got[corev1.ResourceMemory]; mem.String() != "444Mi" {
+		t.Errorf("Memory = %v, want 444Mi", mem)
+	}
+	// cpu: 1000m allocatable - 200m (other pod) = 800m, no buffer on CPU
+	if cpu := got[corev1.ResourceCPU]; cpu.String() != "800m" {
+		t.Errorf("CPU = %v, want 800m", cpu)
+	}
+}
+
+func TestCalculateFitLimitsFloorsAtZero(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "100Mi")
+	pod := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "50Mi")
+	other := podOnNode("default", "other", "n1", corev1.PodRunning, "900m", "900Mi")
+
+	r := newTestReconciler(t, pod, other)
+	config := policy.DefaultConfig()
+
+	got, err := r.calculateFitLimits(context.Background(), pod, node, config)
+	if err != nil {
+		t.Fatalf("calculateFitLimits() error = %v", err)
+	}
+	if mem := got[corev1.ResourceMemory]; mem.Value() != 0 {
+		t.Errorf("Memory = %v, want 0 (available never goes negative)", mem)
+	}
+}
+
+type stubMetricsProvider struct {
+	usage corev1.ResourceList
+	err   error
+}
+
+func (s stubMetricsProvider) NodeUsage(context.Context, string) (corev1.ResourceList, error) {
+	return s.usage, s.err
+}
+
+func TestCalculateColocationLimitsUsesLiveUsage(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "1000Mi")
+	pod := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+	r := newTestReconciler(t, pod)
+	r.MetricsProvider = stubMetricsProvider{usage: corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("300Mi"),
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+	}}
+
+	config := policy.DefaultConfig() // HighWatermark: 90, ReclaimRatio: 100, Buffer: 256Mi
+
+	got, err := r.calculateColocationLimits(context.Background(), pod, node, config)
+	if err != nil {
+		t.Fatalf("calculateColocationLimits() error = %v", err)
+	}
+
+	// memory: ceiling = 1000Mi*90/100 = 900Mi; 900Mi - 300Mi used - 256Mi buffer = 344Mi
+	if mem := got[corev1.ResourceMemory]; mem.String() != "344Mi" {
+		t.Errorf("Memory = %v, want 344Mi", mem)
+	}
+	// cpu: ceiling = 1000m*90/100 = 900m; 900m - 200m used = 700m, no buffer on CPU
+	if cpu := got[corev1.ResourceCPU]; cpu.String() != "700m" {
+		t.Errorf("CPU = %v, want 700m", cpu)
+	}
+}
+
+func TestCalculateColocationLimitsDegradesWithoutMetricsProvider(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "1000Mi")
+	pod := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+	r := newTestReconciler(t, pod)
+	// r.MetricsProvider left nil.
+
+	config := policy.DefaultConfig()
+	config.DegradePolicy = StrategyCap
+
+	got, err := r.calculateColocationLimits(context.Background(), pod, node, config)
+	if err != nil {
+		t.Fatalf("calculateColocationLimits() error = %v", err)
+	}
+	// degradeColocation with DegradePolicy StrategyCap = request, no burst.
+	if mem := got[corev1.ResourceMemory]; mem.String() != "100Mi" {
+		t.Errorf("Memory = %v, want 100Mi (capped to request)", mem)
+	}
+}
+
+func TestCalculateColocationLimitsDegradesOnStaleUsage(t *testing.T) {
+	node := nodeWithAllocatable("n1", "1", "1000Mi")
+	pod := podOnNode("default", "app", "n1", corev1.PodRunning, "100m", "100Mi")
+	r := newTestReconciler(t, pod)
+	r.MetricsProvider = stubMetricsProvider{err: errors.New("no sample within TTL")}
+
+	config := policy.DefaultConfig()
+	config.DegradePolicy = StrategyFit
+
+	got, err := r.calculateColocationLimits(context.Background(), pod, node, config)
+	if err != nil {
+		t.Fatalf("calculateColocationLimits() error = %v", err)
+	}
+	// degradeColocation falls through to calculateFitLimits: 1000Mi - 256Mi buffer (no other pods).
+	if mem := got[corev1.ResourceMemory]; mem.String() != "744Mi" {
+		t.Errorf("Memory = %v, want 744Mi (degraded to fit strategy)", mem)
+	}
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// minResizeServerVersionMajor/Minor is the earliest Kubernetes version
+	// that accepts patches against the pods/resize subresource. Older
+	// servers either reject the patch outright or silently restart the
+	// container, so we fall back to a warning event on them instead.
+	minResizeServerVersionMajor = 1
+	minResizeServerVersionMinor = 27
+
+	// featureGateTTL bounds how often ResizeFeatureGate re-probes the API
+	// server; the answer does not change within the lifetime of a cluster.
+	featureGateTTL = 10 * time.Minute
+
+	resizePollInterval = 500 * time.Millisecond
+	resizePollTimeout  = 10 * time.Second
+	resizeRequeueDelay = 30 * time.Second
+)
+
+// ResizeFeatureGate probes the API server's version once per featureGateTTL
+// to decide whether in-place pod resize is safe to use. A nil
+// *ResizeFeatureGate, or one with no Discovery client set, is treated as
+// unsupported, so wiring it up is optional for callers that don't care.
+type ResizeFeatureGate struct {
+	Discovery discovery.DiscoveryInterface
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	supported bool
+}
+
+// Supported reports whether the API server accepts pods/resize patches.
+func (g *ResizeFeatureGate) Supported(context.Context) bool {
+	if g == nil || g.Discovery == nil {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if time.Since(g.checkedAt) < featureGateTTL {
+		return g.supported
+	}
+
+	version, err := g.Discovery.ServerVersion()
+	if err != nil {
+		// An unreachable discovery endpoint says nothing about the
+		// feature itself, so keep the previous answer and try again
+		// sooner than featureGateTTL next time by not updating checkedAt.
+		return g.supported
+	}
+
+	major, majErr := strconv.Atoi(version.Major)
+	minor, minErr := strconv.Atoi(strings.TrimRight(version.Minor, "+"))
+	g.checkedAt = time.Now()
+	g.supported = majErr == nil && minErr == nil &&
+		(major > minResizeServerVersionMajor ||
+			(major == minResizeServerVersionMajor && minor >= minResizeServerVersionMinor))
+	return g.supported
+}
+
+// containerResizeRestartPolicy returns the effective ResizeRestartPolicy a
+// container declared for resourceName, defaulting to NotRequired per the
+// in-place resize API when the container didn't declare one.
+func containerResizeRestartPolicy(c *corev1.Container, resourceName corev1.ResourceName) corev1.ResourceResizeRestartPolicy {
+	for _, p := range c.ResizePolicy {
+		if p.ResourceName == resourceName {
+			return p.RestartPolicy
+		}
+	}
+	return corev1.NotRequired
+}
+
+// resizeRequiresRestart reports whether applying newLimits to container
+// changes a resource whose ResizePolicy is RestartContainer, meaning the
+// kubelet will restart the container rather than resize it in place.
+func resizeRequiresRestart(container *corev1.Container, newLimits corev1.ResourceList) bool {
+	for resourceName, newQuantity := range newLimits {
+		if current, ok := container.Resources.Limits[resourceName]; ok && current.Equal(newQuantity) {
+			continue
+		}
+		if containerResizeRestartPolicy(container, resourceName) == corev1.RestartContainer {
+			return true
+		}
+	}
+	return false
+}
+
+// containerStatusesReflect reports whether every container in desired has a
+// matching ContainerStatuses[].Resources entry in actual, i.e. the kubelet
+// has actually applied the resize rather than just accepted the request.
+func containerStatusesReflect(actual, desired *corev1.Pod) bool {
+	statuses := make(map[string]*corev1.ResourceRequirements, len(actual.Status.ContainerStatuses))
+	for i := range actual.Status.ContainerStatuses {
+		cs := &actual.Status.ContainerStatuses[i]
+		statuses[cs.Name] = cs.Resources
+	}
+
+	for i := range desired.Spec.Containers {
+		container := &desired.Spec.Containers[i]
+		resources, ok := statuses[container.Name]
+		if !ok || resources == nil {
+			return false
+		}
+		for resourceName, limit := range container.Resources.Limits {
+			current, ok := resources.Limits[resourceName]
+			if !ok || !current.Equal(limit) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// adjustmentReason explains, in the strategy's own terms, why it produced
+// the limits it did. Strategy is already its own field in
+// AnnotationLastAdjustment, so Reason needs to say more than the strategy's
+// name — the knob behind the number an operator would otherwise have to go
+// look up.
+func adjustmentReason(config Config) string {
+	switch config.Strategy {
+	case StrategyPercent:
+		return fmt.Sprintf("recalculated as %d%% of node allocatable", config.Percent)
+	case StrategyFit:
+		return fmt.Sprintf("node headroom recalculated (buffer=%s)", config.Buffer.String())
+	case StrategyCap:
+		return "capped to container requests, no burst allowed"
+	case StrategyColocation:
+		return fmt.Sprintf("colocation headroom recalculated (highWatermark=%d%%, reclaimRatio=%d%%)", config.HighWatermark, config.ReclaimRatio)
+	default:
+		return "limits recalculated"
+	}
+}
+
+// patchPodLimits applies newLimits (keyed by container name) to the matching
+// containers through the pods/resize subresource. Where a changed resource's
+// ResizePolicy is RestartContainer, the kubelet restarts that container as
+// part of applying the resize, which is the restart the container itself
+// opted into; otherwise it resizes the container in place.
+func (r *PodReconciler) patchPodLimits(ctx context.Context, pod *corev1.Pod, newLimits map[string]corev1.ResourceList, config Config) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !r.ResizeGate.Supported(ctx) {
+		logger.Info("API server does not support in-place pod resize, skipping", "pod", client.ObjectKeyFromObject(pod))
+		recordResizeUnsupportedEvent(r.Recorder, pod)
+		// The pod's limits were never touched, so this must be distinguishable
+		// from the genuine-success ctrl.Result{}, nil below: returning the same
+		// shape would make the caller count it as ResultAdjusted instead of
+		// ResultSkipped, and it would repeat every reconcile on a cluster
+		// without the resize feature gate enabled.
+		return ctrl.Result{RequeueAfter: resizeRequeueDelay}, nil
+	}
+
+	original := pod.DeepCopy()
+
+	annotationValue, err := buildLastAdjustmentAnnotation(pod, newLimits, string(config.Strategy), adjustmentReason(config), time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Commit the annotation bookkeeping first, while pod.Spec still matches
+	// original, so this merge patch carries only metadata: Kubernetes rejects
+	// a plain Patch/Update whose diff touches spec.containers[*].resources,
+	// which must go through the resize subresource below instead.
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[AnnotationAdjusted] = "true"
+	pod.Annotations[AnnotationLastAdjustment] = annotationValue
+	delete(pod.Annotations, AnnotationPendingFit)
+	if err := r.Patch(ctx, pod.DeepCopy(), client.MergeFrom(original)); err != nil {
+		recordFailedResizeEvent(r.Recorder, pod, err)
+		return ctrl.Result{}, err
+	}
+
+	restartRequired := false
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		limits, ok := newLimits[container.Name]
+		if !ok {
+			continue
+		}
+		if resizeRequiresRestart(container, limits) {
+			restartRequired = true
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = make(corev1.ResourceList)
+		}
+		for resourceName, limit := range limits {
+			container.Resources.Limits[resourceName] = limit
+		}
+	}
+
+	if restartRequired {
+		logger.Info("Resize requires a container restart per ResizePolicy, the kubelet will restart it while applying the resize", "pod", client.ObjectKeyFromObject(pod))
+	}
+
+	if err := r.SubResource("resize").Update(ctx, pod); err != nil {
+		recordFailedResizeEvent(r.Recorder, pod, err)
+		return ctrl.Result{}, err
+	}
+
+	status, confirmed, err := r.waitForResize(ctx, pod)
+	if err != nil {
+		recordFailedResizeEvent(r.Recorder, pod, err)
+		return ctrl.Result{}, err
+	}
+	if !confirmed {
+		// The kubelet hasn't reached a terminal Status.Resize, or reflected
+		// the new limits in ContainerStatuses, within resizePollTimeout.
+		// Requeue instead of declaring success: needsUpdate will see Spec
+		// already matching the desired limits on the next reconcile (the
+		// resize subresource wrote them regardless of outcome), so it won't
+		// revisit this on its own. handleRejectedResize is what catches a
+		// late Infeasible/Deferred that arrives after this call gives up.
+		logger.Info("Resize not yet confirmed by the kubelet, requeuing", "pod", client.ObjectKeyFromObject(pod))
+		return ctrl.Result{RequeueAfter: resizeRequeueDelay}, nil
+	}
+
+	switch status {
+	case corev1.PodResizeStatusInfeasible:
+		logger.Info("Resize rejected as infeasible, rolling back", "pod", client.ObjectKeyFromObject(pod))
+		if rbErr := r.rollbackPodLimits(ctx, pod, original); rbErr != nil {
+			logger.Error(rbErr, "Failed to roll back pod after infeasible resize")
+		}
+		recordResizeRejectedEvent(r.Recorder, pod, string(status))
+		return ctrl.Result{RequeueAfter: resizeRequeueDelay}, nil
+	case corev1.PodResizeStatusDeferred:
+		logger.Info("Resize deferred by kubelet, will retry", "pod", client.ObjectKeyFromObject(pod))
+		recordResizeRejectedEvent(r.Recorder, pod, string(status))
+		return ctrl.Result{RequeueAfter: resizeRequeueDelay}, nil
+	}
+
+	recordAdjustedEvent(r.Recorder, pod, string(config.Strategy))
+	return ctrl.Result{}, nil
+}
+
+// rollbackPodLimits reverts the adjustment annotations and, via the
+// pods/resize subresource, the container resource limits that an earlier
+// patchPodLimits call applied but the kubelet then rejected: as Infeasible
+// while patchPodLimits was waiting, or as Infeasible or Deferred when
+// handleRejectedResize catches it on a later reconcile.
+// The two reversions are sent separately for the same reason they were
+// applied separately: a plain patch cannot carry a spec.containers[*].resources
+// diff.
+func (r *PodReconciler) rollbackPodLimits(ctx context.Context, pod, original *corev1.Pod) error {
+	revertedAnnotations := pod.DeepCopy()
+	revertedAnnotations.Annotations = original.Annotations
+	if err := r.Patch(ctx, revertedAnnotations, client.MergeFrom(pod)); err != nil {
+		return err
+	}
+
+	for i := range revertedAnnotations.Spec.Containers {
+		revertedAnnotations.Spec.Containers[i].Resources.Limits = original.Spec.Containers[i].Resources.Limits
+	}
+	return r.SubResource("resize").Update(ctx, revertedAnnotations)
+}
+
+// waitForResize polls pod until the kubelet reports a terminal
+// Status.Resize outcome, or its ContainerStatuses reflect the resize having
+// actually been applied, or resizePollTimeout elapses. confirmed is false
+// only in the last case, meaning status cannot be trusted as the final word:
+// the kubelet may still report Infeasible or Deferred after the caller stops
+// waiting, which handleRejectedResize picks up on a later reconcile.
+func (r *PodReconciler) waitForResize(ctx context.Context, pod *corev1.Pod) (status corev1.PodResizeStatus, confirmed bool, err error) {
+	key := client.ObjectKeyFromObject(pod)
+	var latest corev1.Pod
+
+	pollErr := wait.PollUntilContextTimeout(ctx, resizePollInterval, resizePollTimeout, true, func(ctx context.Context) (bool, error) {
+		if err := r.Get(ctx, key, &latest); err != nil {
+			return false, err
+		}
+		switch latest.Status.Resize {
+		case corev1.PodResizeStatusInfeasible, corev1.PodResizeStatusDeferred:
+			return true, nil
+		}
+		return containerStatusesReflect(&latest, pod), nil
+	})
+	if pollErr != nil {
+		if errors.Is(pollErr, context.DeadlineExceeded) {
+			return latest.Status.Resize, false, nil
+		}
+		return "", false, pollErr
+	}
+	return latest.Status.Resize, true, nil
+}
+
+// handleRejectedResize reacts to a kubelet-reported Infeasible or Deferred
+// Status.Resize on a pod nodefit previously resized. It exists because the
+// resize subresource writes the desired limits into Spec as soon as it's
+// accepted, regardless of whether the kubelet later honors them — so
+// needsUpdate, which only diffs Spec against the desired limits, would never
+// notice a rejection that arrives after patchPodLimits stopped waiting for
+// it. handled is true once this has acted (successfully or not), telling the
+// caller to skip the rest of Reconcile for this pass.
+func (r *PodReconciler) handleRejectedResize(ctx context.Context, pod *corev1.Pod) (result ctrl.Result, handled bool, err error) {
+	if pod.Annotations[AnnotationAdjusted] != "true" {
+		return ctrl.Result{}, false, nil
+	}
+	switch pod.Status.Resize {
+	case corev1.PodResizeStatusInfeasible, corev1.PodResizeStatusDeferred:
+	default:
+		return ctrl.Result{}, false, nil
+	}
+
+	rec, err := parseLastAdjustmentRecord(pod)
+	if err != nil {
+		return ctrl.Result{}, false, err
+	}
+
+	original := pod.DeepCopy()
+	delete(original.Annotations, AnnotationAdjusted)
+	delete(original.Annotations, AnnotationLastAdjustment)
+	for i := range original.Spec.Containers {
+		container := &original.Spec.Containers[i]
+		if adjustment, ok := rec.Containers[container.Name]; ok {
+			container.Resources.Limits = adjustment.Old
+		}
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Resize was rejected after nodefit stopped waiting for it, rolling back", "pod", client.ObjectKeyFromObject(pod), "status", pod.Status.Resize)
+	if rbErr := r.rollbackPodLimits(ctx, pod, original); rbErr != nil {
+		logger.Error(rbErr, "Failed to roll back pod after a late resize rejection")
+	}
+	recordResizeRejectedEvent(r.Recorder, pod, string(pod.Status.Resize))
+	return ctrl.Result{RequeueAfter: resizeRequeueDelay}, true, nil
+}
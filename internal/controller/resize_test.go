@@ -0,0 +1,109 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResizeRequiresRestart(t *testing.T) {
+	container := &corev1.Container{
+		Name: "app",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+		ResizePolicy: []corev1.ContainerResizePolicy{
+			{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.RestartContainer},
+		},
+	}
+
+	tests := []struct {
+		name string
+		new  corev1.ResourceList
+		want bool
+	}{
+		{
+			name: "unchanged resource never requires a restart",
+			new:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			want: false,
+		},
+		{
+			name: "changed resource with no declared policy defaults to NotRequired",
+			new:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+			want: false,
+		},
+		{
+			name: "changed resource with RestartContainer policy requires a restart",
+			new:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resizeRequiresRestart(container, tt.new); got != tt.want {
+				t.Errorf("resizeRequiresRestart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerStatusesReflect(t *testing.T) {
+	desired := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		}},
+	}}
+
+	applied := &corev1.Pod{Status: corev1.PodStatus{
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "app",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		}},
+	}}
+	if !containerStatusesReflect(applied, desired) {
+		t.Errorf("containerStatusesReflect() = false, want true once kubelet reports the new limit")
+	}
+
+	stale := &corev1.Pod{Status: corev1.PodStatus{
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "app",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+		}},
+	}}
+	if containerStatusesReflect(stale, desired) {
+		t.Errorf("containerStatusesReflect() = true, want false while ContainerStatuses still shows the old limit")
+	}
+
+	missing := &corev1.Pod{Status: corev1.PodStatus{}}
+	if containerStatusesReflect(missing, desired) {
+		t.Errorf("containerStatusesReflect() = true, want false when the container has no status yet")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// scaledValue converts q to the integer unit the rest of this package does
+// its arithmetic in: milli-units for CPU (to keep sub-core precision), whole
+// units for everything else (bytes for memory/ephemeral-storage/hugepages).
+func scaledValue(q resource.Quantity, name corev1.ResourceName) int64 {
+	if name == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+// quantityFromScaled is the inverse of scaledValue.
+func quantityFromScaled(v int64, name corev1.ResourceName) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(v, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(v, resource.BinarySI)
+}
+
+func addResourceList(list corev1.ResourceList, add corev1.ResourceList) {
+	for name, q := range add {
+		if cur, ok := list[name]; ok {
+			cur.Add(q)
+			list[name] = cur
+		} else {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+func maxResourceList(list corev1.ResourceList, candidate corev1.ResourceList) {
+	for name, q := range candidate {
+		if cur, ok := list[name]; ok {
+			if q.Cmp(cur) > 0 {
+				list[name] = q.DeepCopy()
+			}
+		} else {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+func isRestartableInitContainer(c *corev1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// podEffectiveRequests computes the pod-level request for each resource the
+// same way kubectl describe node does: regular containers plus native
+// sidecars (restartPolicy: Always init containers) are summed, then
+// compared against the peak requirement while init containers are starting
+// up, and the larger of the two wins per resource.
+//
+// See https://github.com/kubernetes/enhancements/tree/master/keps/sig-node/753-sidecar-containers#resources-calculation-for-scheduling-and-pod-admission
+func podEffectiveRequests(pod *corev1.Pod) corev1.ResourceList {
+	reqs := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResourceList(reqs, c.Resources.Requests)
+	}
+
+	restartableInitReqs := corev1.ResourceList{}
+	initReqs := corev1.ResourceList{}
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+
+		if isRestartableInitContainer(c) {
+			// A sidecar runs concurrently with the regular containers for
+			// the pod's entire lifetime, so its request joins the running
+			// total rather than just competing as a startup-phase peak.
+			addResourceList(reqs, c.Resources.Requests)
+			addResourceList(restartableInitReqs, c.Resources.Requests)
+			maxResourceList(initReqs, restartableInitReqs)
+			continue
+		}
+
+		atThisStep := corev1.ResourceList{}
+		addResourceList(atThisStep, c.Resources.Requests)
+		addResourceList(atThisStep, restartableInitReqs)
+		maxResourceList(initReqs, atThisStep)
+	}
+
+	maxResourceList(reqs, initReqs)
+	return reqs
+}
+
+// selectContainers returns the containers an adjustment applies to, in spec
+// order: every container when names is empty, or only the named ones.
+func selectContainers(pod *corev1.Pod, names []string) []*corev1.Container {
+	if len(names) == 0 {
+		out := make([]*corev1.Container, len(pod.Spec.Containers))
+		for i := range pod.Spec.Containers {
+			out[i] = &pod.Spec.Containers[i]
+		}
+		return out
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	var out []*corev1.Container
+	for i := range pod.Spec.Containers {
+		if wanted[pod.Spec.Containers[i].Name] {
+			out = append(out, &pod.Spec.Containers[i])
+		}
+	}
+	return out
+}
+
+// isBestEffortContainer reports whether c declares no requests and no
+// limits at all, i.e. it has no baseline to distribute a pod-level limit
+// proportionally against.
+func isBestEffortContainer(c *corev1.Container) bool {
+	return len(c.Resources.Requests) == 0 && len(c.Resources.Limits) == 0
+}
+
+// PodHasBestEffortContainer reports whether any container an adjustment
+// would touch is BestEffort. Exported so the admission webhook can apply
+// the same skip logic PodReconciler does before a pod is even persisted.
+func PodHasBestEffortContainer(pod *corev1.Pod, containerNames []string) bool {
+	for _, c := range selectContainers(pod, containerNames) {
+		if isBestEffortContainer(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// podCurrentLimits sums the existing Limits of the selected containers per
+// resource, for comparison against a newly calculated pod-level ceiling.
+func podCurrentLimits(pod *corev1.Pod, containerNames []string) corev1.ResourceList {
+	sum := corev1.ResourceList{}
+	for _, c := range selectContainers(pod, containerNames) {
+		addResourceList(sum, c.Resources.Limits)
+	}
+	return sum
+}
+
+// DistributeLimitsAcrossContainers splits a pod-level total limit, per
+// resource, across the selected containers in proportion to each
+// container's current request for that resource, so a container with no
+// request for it gets no proportional share. Only when none of the selected
+// containers declared a request for a resource is it split evenly. The last container
+// (in spec order) absorbs any integer-division remainder so the parts sum
+// exactly to total. Exported so the admission webhook can apply the same
+// split PodReconciler does before a pod is even persisted.
+func DistributeLimitsAcrossContainers(pod *corev1.Pod, total corev1.ResourceList, containerNames []string) map[string]corev1.ResourceList {
+	targets := selectContainers(pod, containerNames)
+	result := make(map[string]corev1.ResourceList, len(targets))
+	for _, c := range targets {
+		result[c.Name] = corev1.ResourceList{}
+	}
+	if len(targets) == 0 {
+		return result
+	}
+
+	for resourceName, totalQuantity := range total {
+		totalScaled := scaledValue(totalQuantity, resourceName)
+
+		shares := make([]int64, len(targets))
+		var sumRequests int64
+		for i, c := range targets {
+			if req, ok := c.Resources.Requests[resourceName]; ok {
+				shares[i] = scaledValue(req, resourceName)
+			}
+			sumRequests += shares[i]
+		}
+
+		var distributed int64
+		for i, c := range targets {
+			var share int64
+			switch {
+			case i == len(targets)-1:
+				share = totalScaled - distributed
+			case sumRequests == 0:
+				share = totalScaled / int64(len(targets))
+			default:
+				share = totalScaled * shares[i] / sumRequests
+			}
+			distributed += share
+			result[c.Name][resourceName] = quantityFromScaled(share, resourceName)
+		}
+	}
+
+	return result
+}
+
+// PlaceholderLimits computes a conservative per-container floor for use by
+// the admission webhook when it can't predict the pod's eventual Node and so
+// can't run the real strategy yet: each selected container's own request
+// plus config.Buffer, per resource in config.Resources, or just
+// config.Buffer when the container declared no request. PodReconciler
+// replaces this with the real calculation on its first reconcile after the
+// pod is scheduled.
+func PlaceholderLimits(pod *corev1.Pod, config Config) map[string]corev1.ResourceList {
+	targets := selectContainers(pod, config.ContainerNames)
+	result := make(map[string]corev1.ResourceList, len(targets))
+	for _, c := range targets {
+		limits := corev1.ResourceList{}
+		for _, resourceName := range config.Resources {
+			floor := scaledValue(config.Buffer, resourceName)
+			if req, ok := c.Resources.Requests[resourceName]; ok {
+				floor += scaledValue(req, resourceName)
+			}
+			limits[resourceName] = quantityFromScaled(floor, resourceName)
+		}
+		result[c.Name] = limits
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func containerWithRequest(name string, cpu, memory string) corev1.Container {
+	return corev1.Container{
+		Name: name,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func restartableInitContainer(name string, cpu, memory string) corev1.Container {
+	c := containerWithRequest(name, cpu, memory)
+	always := corev1.ContainerRestartPolicyAlways
+	c.RestartPolicy = &always
+	return c
+}
+
+func TestPodEffectiveRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        corev1.Pod
+		wantCPU    string
+		wantMemory string
+	}{
+		{
+			name: "regular containers only",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					containerWithRequest("app", "100m", "128Mi"),
+					containerWithRequest("helper", "50m", "64Mi"),
+				},
+			}},
+			wantCPU:    "150m",
+			wantMemory: "192Mi",
+		},
+		{
+			name: "sidecar runs concurrently with the regular container, so its request adds rather than maxes",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					restartableInitContainer("sidecar", "10m", "200Mi"),
+				},
+				Containers: []corev1.Container{
+					containerWithRequest("app", "100m", "100Mi"),
+				},
+			}},
+			// The sidecar outlives startup, so app(100m/100Mi) + sidecar(10m/200Mi)
+			// = 110m/300Mi, which still beats the startup-phase peak (just the
+			// sidecar alone, since there's no other init container).
+			wantCPU:    "110m",
+			wantMemory: "300Mi",
+		},
+		{
+			name: "sidecar's steady-state request adds to a single regular container's sum",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					restartableInitContainer("sidecar", "100m", "50Mi"),
+				},
+				Containers: []corev1.Container{
+					containerWithRequest("app", "200m", "200Mi"),
+				},
+			}},
+			// app(200m) + sidecar(100m) = 300m, matching kubectl/kubelet semantics
+			// for a pod with one sidecar and one regular container.
+			wantCPU:    "300m",
+			wantMemory: "250Mi",
+		},
+		{
+			name: "non-restartable init container peak wins over container sum",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					containerWithRequest("setup", "500m", "512Mi"),
+				},
+				Containers: []corev1.Container{
+					containerWithRequest("app", "100m", "128Mi"),
+				},
+			}},
+			wantCPU:    "500m",
+			wantMemory: "512Mi",
+		},
+		{
+			name: "restartable init container's request persists through later init steps",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					restartableInitContainer("sidecar", "50m", "64Mi"),
+					containerWithRequest("setup", "100m", "100Mi"),
+				},
+				Containers: []corev1.Container{
+					containerWithRequest("app", "50m", "50Mi"),
+				},
+			}},
+			// Peak at the second init step is sidecar(50m/64Mi) + setup(100m/100Mi) = 150m/164Mi,
+			// which beats the steady-state sum of app(50m/50Mi) + sidecar(50m/64Mi) = 100m/114Mi.
+			wantCPU:    "150m",
+			wantMemory: "164Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := podEffectiveRequests(&tt.pod)
+			wantCPU := resource.MustParse(tt.wantCPU)
+			wantMemory := resource.MustParse(tt.wantMemory)
+			if cpu := got[corev1.ResourceCPU]; !cpu.Equal(wantCPU) {
+				t.Errorf("cpu = %s, want %s", cpu.String(), wantCPU.String())
+			}
+			if mem := got[corev1.ResourceMemory]; !mem.Equal(wantMemory) {
+				t.Errorf("memory = %s, want %s", mem.String(), wantMemory.String())
+			}
+		})
+	}
+}
+
+func TestDistributeLimitsAcrossContainers(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			containerWithRequest("a", "100m", "100Mi"),
+			containerWithRequest("b", "200m", "100Mi"),
+			containerWithRequest("c", "0", "100Mi"),
+		},
+	}}
+	pod.Spec.Containers[2].Resources.Requests = corev1.ResourceList{}
+
+	total := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("300m"),
+	}
+
+	got := DistributeLimitsAcrossContainers(pod, total, nil)
+
+	var sum resource.Quantity
+	for _, name := range []string{"a", "b", "c"} {
+		sum.Add(got[name][corev1.ResourceCPU])
+	}
+	wantTotal := resource.MustParse("300m")
+	if !sum.Equal(wantTotal) {
+		t.Fatalf("shares sum to %s, want %s", sum.String(), wantTotal.String())
+	}
+
+	cQuantity := got["c"][corev1.ResourceCPU]
+	if cQuantity.MilliValue() != 0 {
+		t.Errorf("container with no request got a nonzero proportional share: %s", cQuantity.String())
+	}
+}
+
+func TestDistributeLimitsAcrossContainersAllZeroRequestsSplitsEvenly(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "a"},
+			{Name: "b"},
+		},
+	}}
+
+	total := corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("200Mi"),
+	}
+
+	got := DistributeLimitsAcrossContainers(pod, total, nil)
+
+	wantEach := resource.MustParse("100Mi")
+	if mem := got["a"][corev1.ResourceMemory]; !mem.Equal(wantEach) {
+		t.Errorf("container a = %s, want %s", mem.String(), wantEach.String())
+	}
+	if mem := got["b"][corev1.ResourceMemory]; !mem.Equal(wantEach) {
+		t.Errorf("container b = %s, want %s", mem.String(), wantEach.String())
+	}
+}
+
+func TestDistributeLimitsAcrossContainersHonorsContainerNames(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			containerWithRequest("app", "100m", "100Mi"),
+			containerWithRequest("sidecar", "100m", "100Mi"),
+		},
+	}}
+
+	total := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+	got := DistributeLimitsAcrossContainers(pod, total, []string{"app"})
+
+	if _, ok := got["sidecar"]; ok {
+		t.Fatalf("sidecar was not selected by containerNames but got a share: %v", got)
+	}
+	wantApp := resource.MustParse("100m")
+	if cpu := got["app"][corev1.ResourceCPU]; !cpu.Equal(wantApp) {
+		t.Errorf("app = %s, want %s", cpu.String(), wantApp.String())
+	}
+}
+
+func TestPodHasBestEffortContainer(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			containerWithRequest("app", "100m", "100Mi"),
+			{Name: "besteffort"},
+		},
+	}}
+
+	if PodHasBestEffortContainer(pod, []string{"app"}) {
+		t.Errorf("selecting only 'app' should not see the BestEffort container")
+	}
+	if !PodHasBestEffortContainer(pod, nil) {
+		t.Errorf("selecting every container should see the BestEffort one")
+	}
+}
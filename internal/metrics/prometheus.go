@@ -0,0 +1,65 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Result labels used on AdjustmentsTotal.
+const (
+	ResultAdjusted = "adjusted"
+	ResultNoop     = "noop"
+	ResultSkipped  = "skipped"
+	ResultError    = "error"
+)
+
+var (
+	// AdjustmentsTotal counts every reconcile outcome for a nodefit-enabled
+	// pod, by strategy and result.
+	AdjustmentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nodefit_adjustments_total",
+		Help: "Total number of pod limit adjustment attempts, by strategy and result.",
+	}, []string{"strategy", "result"})
+
+	// PodLimitBytes is the pod-level limit nodefit computed for a resource,
+	// before it is split across containers.
+	PodLimitBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodefit_pod_limit_bytes",
+		Help: "Pod-level resource limit computed by nodefit-controller, by pod, resource, and strategy.",
+	}, []string{"pod", "resource", "strategy"})
+
+	// NodeAvailableBytes is a node's headroom: allocatable minus the summed
+	// effective requests of its Running and Pending pods, independent of the
+	// strategy or the limit computed for any one pod.
+	NodeAvailableBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodefit_node_available_bytes",
+		Help: "Node resource headroom computed by nodefit-controller, by node and resource.",
+	}, []string{"node", "resource"})
+
+	// ReconcileDuration measures PodReconciler.Reconcile latency.
+	ReconcileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "nodefit_reconcile_duration_seconds",
+		Help:    "Duration of PodReconciler.Reconcile calls in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(AdjustmentsTotal, PodLimitBytes, NodeAvailableBytes, ReconcileDuration)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics provides live node resource usage to the colocation
+// strategy, pulled from metrics.k8s.io and refreshed on a fixed interval.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrStale is returned by NodeUsage when no sample for the node has been
+// observed within the provider's TTL, signaling callers to fall back to the
+// degrade policy rather than act on outdated usage.
+var ErrStale = errors.New("metrics: no usage sample within TTL")
+
+// NodeMetricsProvider abstracts where actual node utilization comes from so
+// the colocation strategy can be tested without a live metrics-server.
+type NodeMetricsProvider interface {
+	// NodeUsage returns the most recently observed resource usage for node.
+	// It returns ErrStale if the sample is older than the provider's TTL.
+	NodeUsage(ctx context.Context, node string) (corev1.ResourceList, error)
+}
+
+type sample struct {
+	usage      corev1.ResourceList
+	observedAt time.Time
+}
+
+// CachedNodeMetricsProvider implements NodeMetricsProvider on top of the
+// metrics.k8s.io API, polling NodeMetricses on Interval and caching the
+// latest sample per node for up to TTL.
+type CachedNodeMetricsProvider struct {
+	client   metricsclientset.Interface
+	interval time.Duration
+	ttl      time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]sample
+}
+
+// NewCachedNodeMetricsProvider returns a provider that polls client every
+// interval and treats samples older than ttl as stale.
+func NewCachedNodeMetricsProvider(client metricsclientset.Interface, interval, ttl time.Duration) *CachedNodeMetricsProvider {
+	return &CachedNodeMetricsProvider{
+		client:   client,
+		interval: interval,
+		ttl:      ttl,
+		cache:    make(map[string]sample),
+	}
+}
+
+// NodeUsage implements NodeMetricsProvider.
+func (p *CachedNodeMetricsProvider) NodeUsage(ctx context.Context, node string) (corev1.ResourceList, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	s, ok := p.cache[node]
+	if !ok || time.Since(s.observedAt) > p.ttl {
+		return nil, ErrStale
+	}
+	return s.usage, nil
+}
+
+// Start implements manager.Runnable so the collector can be registered with
+// mgr.Add and share the manager's lifecycle and leader-election gating.
+func (p *CachedNodeMetricsProvider) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("node-metrics-provider")
+
+	p.collect(ctx, logger)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.collect(ctx, logger)
+		}
+	}
+}
+
+func (p *CachedNodeMetricsProvider) collect(ctx context.Context, logger logr.Logger) {
+	list, err := p.client.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.Error(err, "Failed to list node metrics")
+		return
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, nm := range list.Items {
+		p.cache[nm.Name] = sample{usage: nm.Usage, observedAt: now}
+	}
+}
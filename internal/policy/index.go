@@ -0,0 +1,255 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy resolves the effective NodeFitPolicy configuration for a
+// pod by picking, by precedence, one of the cluster-scoped NodeFitPolicy and
+// namespace-scoped NamespaceNodeFitPolicy objects that select it. Fields of
+// lower-precedence matches are not merged in; unset fields take defaults.
+package policy
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodefitv1alpha1 "github.com/KamorionLabs/node-fit-controller/api/v1alpha1"
+)
+
+// ErrNoPolicy is returned by Resolve when no NodeFitPolicy or
+// NamespaceNodeFitPolicy selects the pod, so callers can fall back to
+// annotation-based configuration for backward compatibility.
+var ErrNoPolicy = errors.New("policy: no NodeFitPolicy matches pod")
+
+// Config is the effective, already-resolved configuration for a single pod.
+// It plays the same role the old annotation-derived Config struct did.
+type Config struct {
+	Strategy       nodefitv1alpha1.Strategy
+	Percent        int
+	Buffer         resource.Quantity
+	Resources      []corev1.ResourceName
+	ContainerNames []string
+
+	// HighWatermark, ReclaimRatio, and DegradePolicy only apply to
+	// StrategyColocation; see PolicyRule for their semantics.
+	HighWatermark int
+	ReclaimRatio  int
+	DegradePolicy nodefitv1alpha1.Strategy
+}
+
+// DefaultConfig mirrors the historical annotation defaults.
+func DefaultConfig() Config {
+	return Config{
+		Strategy:      nodefitv1alpha1.StrategyPercent,
+		Percent:       80,
+		Buffer:        resource.MustParse("256Mi"),
+		Resources:     []corev1.ResourceName{corev1.ResourceMemory, corev1.ResourceCPU},
+		HighWatermark: 90,
+		ReclaimRatio:  100,
+		DegradePolicy: nodefitv1alpha1.StrategyFit,
+	}
+}
+
+// Index resolves the effective Config for a pod by reading NodeFitPolicy and
+// NamespaceNodeFitPolicy objects out of the manager's cache.
+type Index struct {
+	client.Client
+}
+
+// NewIndex returns a policy Index backed by c. c is expected to be a
+// manager's cached client so Resolve does not hit the API server directly.
+func NewIndex(c client.Client) *Index {
+	return &Index{Client: c}
+}
+
+type candidate struct {
+	rule       nodefitv1alpha1.PolicyRule
+	name       string
+	namespaced bool
+}
+
+// Resolve returns the effective Config for pod, or ErrNoPolicy if no policy
+// selects it.
+func (idx *Index) Resolve(ctx context.Context, pod *corev1.Pod) (Config, error) {
+	var namespace corev1.Namespace
+	if err := idx.Get(ctx, types.NamespacedName{Name: pod.Namespace}, &namespace); err != nil {
+		return Config{}, err
+	}
+
+	var candidates []candidate
+
+	var clusterPolicies nodefitv1alpha1.NodeFitPolicyList
+	if err := idx.List(ctx, &clusterPolicies); err != nil {
+		return Config{}, err
+	}
+	for _, p := range clusterPolicies.Items {
+		matches, err := selectorMatches(p.Spec.NamespaceSelector, namespace.Labels)
+		if err != nil {
+			return Config{}, err
+		}
+		if !matches {
+			continue
+		}
+		matches, err = selectorMatches(p.Spec.WorkloadSelector, pod.Labels)
+		if err != nil {
+			return Config{}, err
+		}
+		if !matches {
+			continue
+		}
+		candidates = append(candidates, candidate{rule: p.Spec.PolicyRule, name: p.Name})
+	}
+
+	var nsPolicies nodefitv1alpha1.NamespaceNodeFitPolicyList
+	if err := idx.List(ctx, &nsPolicies, client.InNamespace(pod.Namespace)); err != nil {
+		return Config{}, err
+	}
+	for _, p := range nsPolicies.Items {
+		matches, err := selectorMatches(p.Spec.WorkloadSelector, pod.Labels)
+		if err != nil {
+			return Config{}, err
+		}
+		if !matches {
+			continue
+		}
+		candidates = append(candidates, candidate{rule: p.Spec.PolicyRule, name: p.Name, namespaced: true})
+	}
+
+	if len(candidates) == 0 {
+		return Config{}, ErrNoPolicy
+	}
+
+	// Precedence: namespace-scoped beats cluster-scoped, then higher
+	// Priority wins, then lexical name order for determinism.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.namespaced != b.namespaced {
+			return a.namespaced
+		}
+		if a.rule.Priority != b.rule.Priority {
+			return a.rule.Priority > b.rule.Priority
+		}
+		return a.name < b.name
+	})
+
+	return configFromRule(candidates[0].rule), nil
+}
+
+// MatchingPods returns the pods currently selected by a NodeFitPolicy or
+// NamespaceNodeFitPolicy object. It is the reverse of Resolve (pod -> policy)
+// and exists for callers that need to go the other way: re-enqueuing the
+// pods affected by a policy that just changed.
+func (idx *Index) MatchingPods(ctx context.Context, obj client.Object) ([]types.NamespacedName, error) {
+	switch p := obj.(type) {
+	case *nodefitv1alpha1.NodeFitPolicy:
+		return idx.matchingPods(ctx, "", p.Spec.NamespaceSelector, p.Spec.WorkloadSelector)
+	case *nodefitv1alpha1.NamespaceNodeFitPolicy:
+		return idx.matchingPods(ctx, p.Namespace, nil, p.Spec.WorkloadSelector)
+	default:
+		return nil, nil
+	}
+}
+
+// matchingPods lists the pods in namespace (or, if namespace is empty, every
+// namespace matching namespaceSelector) whose labels satisfy workloadSelector.
+func (idx *Index) matchingPods(ctx context.Context, namespace string, namespaceSelector, workloadSelector *metav1.LabelSelector) ([]types.NamespacedName, error) {
+	var namespaces []corev1.Namespace
+	if namespace != "" {
+		var ns corev1.Namespace
+		if err := idx.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+			return nil, err
+		}
+		namespaces = []corev1.Namespace{ns}
+	} else {
+		var nsList corev1.NamespaceList
+		if err := idx.List(ctx, &nsList); err != nil {
+			return nil, err
+		}
+		namespaces = nsList.Items
+	}
+
+	var keys []types.NamespacedName
+	for _, ns := range namespaces {
+		matches, err := selectorMatches(namespaceSelector, ns.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+
+		var podList corev1.PodList
+		if err := idx.List(ctx, &podList, client.InNamespace(ns.Name)); err != nil {
+			return nil, err
+		}
+		for _, pod := range podList.Items {
+			matches, err := selectorMatches(workloadSelector, pod.Labels)
+			if err != nil {
+				return nil, err
+			}
+			if matches {
+				keys = append(keys, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name})
+			}
+		}
+	}
+	return keys, nil
+}
+
+func configFromRule(rule nodefitv1alpha1.PolicyRule) Config {
+	cfg := DefaultConfig()
+
+	if rule.Strategy != "" {
+		cfg.Strategy = rule.Strategy
+	}
+	if rule.Percent > 0 {
+		cfg.Percent = rule.Percent
+	}
+	if rule.Buffer != nil {
+		cfg.Buffer = *rule.Buffer
+	}
+	if len(rule.Resources) > 0 {
+		cfg.Resources = rule.Resources
+	}
+	cfg.ContainerNames = rule.ContainerNames
+	if rule.HighWatermark > 0 {
+		cfg.HighWatermark = rule.HighWatermark
+	}
+	if rule.ReclaimRatio > 0 {
+		cfg.ReclaimRatio = rule.ReclaimRatio
+	}
+	if rule.DegradePolicy != "" {
+		cfg.DegradePolicy = rule.DegradePolicy
+	}
+
+	return cfg
+}
+
+func selectorMatches(selector *metav1.LabelSelector, podOrNamespaceLabels map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return sel.Matches(labels.Set(podOrNamespaceLabels)), nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nodefitv1alpha1 "github.com/KamorionLabs/node-fit-controller/api/v1alpha1"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme: %v", err)
+	}
+	if err := nodefitv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("nodefitv1alpha1.AddToScheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestIndex(t *testing.T, objs ...client.Object) *Index {
+	t.Helper()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build()
+	return NewIndex(c)
+}
+
+func TestResolveFallsBackToErrNoPolicy(t *testing.T) {
+	idx := newTestIndex(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+	if _, err := idx.Resolve(context.Background(), pod); err != ErrNoPolicy {
+		t.Fatalf("Resolve() error = %v, want ErrNoPolicy", err)
+	}
+}
+
+func TestResolveNamespacePolicyBeatsClusterPolicy(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	cluster := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyCap, Priority: 100},
+		},
+	}
+	namespaced := &nodefitv1alpha1.NamespaceNodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ns-scoped"},
+		Spec: nodefitv1alpha1.NamespaceNodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyFit, Priority: 0},
+		},
+	}
+
+	idx := newTestIndex(t, ns, cluster, namespaced)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+
+	cfg, err := idx.Resolve(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if cfg.Strategy != nodefitv1alpha1.StrategyFit {
+		t.Errorf("Strategy = %s, want %s (namespace-scoped should win despite lower priority)", cfg.Strategy, nodefitv1alpha1.StrategyFit)
+	}
+}
+
+func TestResolveHigherPriorityWinsAmongClusterPolicies(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	low := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "low"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyCap, Priority: 1},
+		},
+	}
+	high := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "high"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyFit, Priority: 10},
+		},
+	}
+
+	idx := newTestIndex(t, ns, low, high)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}
+
+	cfg, err := idx.Resolve(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if cfg.Strategy != nodefitv1alpha1.StrategyFit {
+		t.Errorf("Strategy = %s, want %s (higher priority should win)", cfg.Strategy, nodefitv1alpha1.StrategyFit)
+	}
+}
+
+func TestResolveWorkloadSelectorFiltersPods(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	policy := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "batch-only"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			PolicyRule: nodefitv1alpha1.PolicyRule{
+				Strategy:         nodefitv1alpha1.StrategyFit,
+				WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "batch"}},
+			},
+		},
+	}
+
+	idx := newTestIndex(t, ns, policy)
+
+	matching := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "batch", Labels: map[string]string{"tier": "batch"}}}
+	if _, err := idx.Resolve(context.Background(), matching); err != nil {
+		t.Fatalf("Resolve(matching) error = %v", err)
+	}
+
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", Labels: map[string]string{"tier": "web"}}}
+	if _, err := idx.Resolve(context.Background(), other); err != ErrNoPolicy {
+		t.Fatalf("Resolve(other) error = %v, want ErrNoPolicy", err)
+	}
+}
+
+func TestConfigFromRuleAppliesDefaultsForZeroValues(t *testing.T) {
+	cfg := configFromRule(nodefitv1alpha1.PolicyRule{Strategy: nodefitv1alpha1.StrategyColocation})
+
+	def := DefaultConfig()
+	if cfg.Strategy != nodefitv1alpha1.StrategyColocation {
+		t.Errorf("Strategy = %s, want %s", cfg.Strategy, nodefitv1alpha1.StrategyColocation)
+	}
+	if cfg.Percent != def.Percent {
+		t.Errorf("Percent = %d, want default %d", cfg.Percent, def.Percent)
+	}
+	if cfg.HighWatermark != def.HighWatermark {
+		t.Errorf("HighWatermark = %d, want default %d", cfg.HighWatermark, def.HighWatermark)
+	}
+}
+
+func TestMatchingPodsClusterPolicyHonorsNamespaceAndWorkloadSelectors(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+	dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}}
+
+	prodBatch := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "batch", Labels: map[string]string{"tier": "batch"}}}
+	prodWeb := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web", Labels: map[string]string{"tier": "web"}}}
+	devBatch := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "batch", Labels: map[string]string{"tier": "batch"}}}
+
+	policy := &nodefitv1alpha1.NodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-batch"},
+		Spec: nodefitv1alpha1.NodeFitPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			PolicyRule: nodefitv1alpha1.PolicyRule{
+				WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "batch"}},
+			},
+		},
+	}
+
+	idx := newTestIndex(t, prod, dev, prodBatch, prodWeb, devBatch)
+
+	keys, err := idx.MatchingPods(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("MatchingPods() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Namespace != "prod" || keys[0].Name != "batch" {
+		t.Fatalf("MatchingPods() = %v, want exactly prod/batch", keys)
+	}
+}
+
+func TestMatchingPodsNamespacePolicyIsScopedToItsNamespace(t *testing.T) {
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod"}}
+	dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}}
+
+	prodPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "app"}}
+	devPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "app"}}
+
+	policy := &nodefitv1alpha1.NamespaceNodeFitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "scoped"},
+	}
+
+	idx := newTestIndex(t, prod, dev, prodPod, devPod)
+
+	keys, err := idx.MatchingPods(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("MatchingPods() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Namespace != "prod" || keys[0].Name != "app" {
+		t.Fatalf("MatchingPods() = %v, want exactly prod/app", keys)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook holds the admission webhooks that complement
+// PodReconciler by acting before a pod is ever persisted.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/KamorionLabs/node-fit-controller/internal/controller"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod.nodefit.io,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list
+// +kubebuilder:rbac:groups=nodefit.io,resources=nodefitpolicies;namespacenodefitpolicies,verbs=get;list;watch
+
+// PodMutator pre-computes nodefit limits on pod CREATE, covering the first
+// OOMKill window before PodReconciler's first reconcile would otherwise run.
+// It only stamps strategy-computed limits when the scheduler's eventual
+// decision is already predictable (spec.nodeName set, or a hard node affinity
+// that narrows to exactly one Node); otherwise it stamps PlaceholderLimits and
+// marks the pod nodefit.io/pending-fit so operators know nodefit hasn't
+// adjusted it yet.
+type PodMutator struct {
+	// Reconciler supplies the Client, PolicyIndex, MetricsProvider, and
+	// ResolveConfig/CalculateLimits logic this webhook reuses verbatim, so
+	// a pod's limits at admission time come from the same code PodReconciler
+	// runs after it is scheduled. The pod does not exist yet at admission, so
+	// the percent strategy does not count it among the pods on the Node.
+	Reconciler *controller.PodReconciler
+
+	decoder admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (m *PodMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := m.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	config, err := m.Reconciler.ResolveConfig(ctx, pod)
+	if err != nil {
+		if err == controller.ErrNoConfig {
+			return admission.Allowed("pod is not selected by any NodeFitPolicy or annotation")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if controller.PodHasBestEffortContainer(pod, config.ContainerNames) {
+		return admission.Allowed("pod has a BestEffort container, nodefit will skip it")
+	}
+
+	resolver, err := m.resolveTargetNode(ctx, pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if resolver == nil {
+		return m.patched(req, markPendingFit(pod, config))
+	}
+
+	totalLimits, ok, err := m.Reconciler.CalculateLimits(ctx, pod, resolver, config)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !ok {
+		return m.patched(req, markPendingFit(pod, config))
+	}
+
+	newLimits := controller.DistributeLimitsAcrossContainers(pod, totalLimits, config.ContainerNames)
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		limits, ok := newLimits[c.Name]
+		if !ok {
+			continue
+		}
+		if c.Resources.Limits == nil {
+			c.Resources.Limits = make(corev1.ResourceList)
+		}
+		for resourceName, limit := range limits {
+			c.Resources.Limits[resourceName] = limit
+		}
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[controller.AnnotationAdjusted] = "true"
+	delete(pod.Annotations, controller.AnnotationPendingFit)
+
+	return m.patched(req, pod)
+}
+
+// markPendingFit sets nodefit.io/pending-fit on pod, since this webhook
+// couldn't predict the Node. PodReconciler does not read the annotation: it
+// adjusts the pod on its first reconcile once the pod is scheduled and
+// Running, and removes the annotation when it patches the pod's limits. A
+// pod admitted this way still
+// starts running before that first reconcile, so it
+// also gets a conservative PlaceholderLimits floor instead of going out with
+// no limit at all — the gap that leaves open is exactly the pre-scheduling
+// OOMKill window this webhook exists to close.
+func markPendingFit(pod *corev1.Pod, config controller.Config) *corev1.Pod {
+	for name, limits := range controller.PlaceholderLimits(pod, config) {
+		for i := range pod.Spec.Containers {
+			c := &pod.Spec.Containers[i]
+			if c.Name != name {
+				continue
+			}
+			if c.Resources.Limits == nil {
+				c.Resources.Limits = make(corev1.ResourceList)
+			}
+			for resourceName, limit := range limits {
+				c.Resources.Limits[resourceName] = limit
+			}
+		}
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[controller.AnnotationPendingFit] = "true"
+	return pod
+}
+
+// resolveTargetNode tries to predict the Node pod will land on before it
+// exists. A nil resolver (with a nil error) means the target Node isn't
+// predictable yet, not that anything went wrong.
+func (m *PodMutator) resolveTargetNode(ctx context.Context, pod *corev1.Pod) (controller.NodeResolver, error) {
+	if pod.Spec.NodeName != "" {
+		var node corev1.Node
+		if err := m.Reconciler.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, &node); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		return controller.NewFixedNodeResolver(&node), nil
+	}
+
+	selector, ok := hardNodeAffinitySelector(pod)
+	if !ok {
+		return nil, nil
+	}
+
+	var nodeList corev1.NodeList
+	if err := m.Reconciler.List(ctx, &nodeList); err != nil {
+		return nil, err
+	}
+
+	var matched *corev1.Node
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if matched != nil {
+			// More than one Node satisfies the affinity; the scheduler's
+			// eventual choice isn't predictable, so don't guess.
+			return nil, nil
+		}
+		matched = node
+	}
+	if matched == nil {
+		return nil, nil
+	}
+	return controller.NewFixedNodeResolver(matched), nil
+}
+
+// hardNodeAffinitySelector converts a pod's hard (Required) node affinity
+// into a label selector, when it's simple enough to evaluate at admission
+// time: exactly one NodeSelectorTerm. Multiple terms are ORed together,
+// which admission time can't narrow down to a single Node.
+func hardNodeAffinitySelector(pod *corev1.Pod) (labels.Selector, bool) {
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil {
+		return nil, false
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) != 1 {
+		return nil, false
+	}
+
+	term := required.NodeSelectorTerms[0]
+	if len(term.MatchFields) > 0 {
+		// Field selectors (e.g. metadata.name) aren't label-based; leave
+		// prediction to the pod.Spec.NodeName case instead.
+		return nil, false
+	}
+
+	sel := labels.NewSelector()
+	for _, expr := range term.MatchExpressions {
+		op, ok := nodeSelectorOperatorToSelection(expr.Operator)
+		if !ok {
+			return nil, false
+		}
+		req, err := labels.NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			return nil, false
+		}
+		sel = sel.Add(*req)
+	}
+	return sel, true
+}
+
+func nodeSelectorOperatorToSelection(op corev1.NodeSelectorOperator) (selection.Operator, bool) {
+	switch op {
+	case corev1.NodeSelectorOpIn:
+		return selection.In, true
+	case corev1.NodeSelectorOpNotIn:
+		return selection.NotIn, true
+	case corev1.NodeSelectorOpExists:
+		return selection.Exists, true
+	case corev1.NodeSelectorOpDoesNotExist:
+		return selection.DoesNotExist, true
+	default:
+		// Gt/Lt aren't expressible as a label selector requirement.
+		return "", false
+	}
+}
+
+func (m *PodMutator) patched(req admission.Request, pod *corev1.Pod) admission.Response {
+	marshaled, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// SetupWebhookWithManager registers the mutating webhook with mgr. The
+// webhook server's TLS certificate is expected to be provisioned the usual
+// kubebuilder way: a cert-manager Certificate mounted into the manager Pod
+// and referenced by the MutatingWebhookConfiguration's caBundle via the
+// cert-manager.io/inject-ca-from annotation.
+func (m *PodMutator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	m.decoder = admission.NewDecoder(mgr.GetScheme())
+	mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{Handler: m})
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 KamorionLabs.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/KamorionLabs/node-fit-controller/internal/controller"
+)
+
+func newTestMutator(t *testing.T, objs ...client.Object) *PodMutator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("corev1.AddToScheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &PodMutator{Reconciler: &controller.PodReconciler{Client: c}}
+}
+
+func podWithRequiredAffinity(terms ...corev1.NodeSelectorTerm) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: terms,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveTargetNodeUsesNodeNameWhenSet(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+	m := newTestMutator(t, node)
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "n1"}}
+	resolver, err := m.resolveTargetNode(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("resolveTargetNode() error = %v", err)
+	}
+	if resolver == nil {
+		t.Fatal("resolveTargetNode() = nil, want a resolver for an already-scheduled pod")
+	}
+
+	got, ok, err := resolver.ResolveNode(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("ResolveNode() = %v, %v, %v", got, ok, err)
+	}
+	if got.Name != "n1" {
+		t.Errorf("ResolveNode() = %s, want n1", got.Name)
+	}
+}
+
+func TestResolveTargetNodeReturnsNilWhenNodeNameNotYetSet(t *testing.T) {
+	m := newTestMutator(t)
+	pod := &corev1.Pod{}
+
+	resolver, err := m.resolveTargetNode(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("resolveTargetNode() error = %v", err)
+	}
+	if resolver != nil {
+		t.Error("resolveTargetNode() != nil, want nil when nothing narrows the target Node down")
+	}
+}
+
+func TestResolveTargetNodeNarrowsHardAffinityToASingleNode(t *testing.T) {
+	zoneA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Labels: map[string]string{"zone": "a"}}}
+	zoneB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "zone-b", Labels: map[string]string{"zone": "b"}}}
+	m := newTestMutator(t, zoneA, zoneB)
+
+	pod := podWithRequiredAffinity(corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+		},
+	})
+
+	resolver, err := m.resolveTargetNode(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("resolveTargetNode() error = %v", err)
+	}
+	if resolver == nil {
+		t.Fatal("resolveTargetNode() = nil, want a resolver when exactly one Node matches")
+	}
+	got, ok, err := resolver.ResolveNode(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("ResolveNode() = %v, %v, %v", got, ok, err)
+	}
+	if got.Name != "zone-a" {
+		t.Errorf("ResolveNode() = %s, want zone-a", got.Name)
+	}
+}
+
+func TestResolveTargetNodeBailsOutWhenMultipleNodesMatch(t *testing.T) {
+	nodeA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"zone": "a"}}}
+	nodeB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"zone": "a"}}}
+	m := newTestMutator(t, nodeA, nodeB)
+
+	pod := podWithRequiredAffinity(corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+		},
+	})
+
+	resolver, err := m.resolveTargetNode(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("resolveTargetNode() error = %v", err)
+	}
+	if resolver != nil {
+		t.Error("resolveTargetNode() != nil, want nil: the scheduler's eventual choice between two matches isn't predictable")
+	}
+}
+
+func TestResolveTargetNodeBailsOutWhenNoNodeMatches(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{"zone": "a"}}}
+	m := newTestMutator(t, node)
+
+	pod := podWithRequiredAffinity(corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+		},
+	})
+
+	resolver, err := m.resolveTargetNode(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("resolveTargetNode() error = %v", err)
+	}
+	if resolver != nil {
+		t.Error("resolveTargetNode() != nil, want nil when no Node satisfies the affinity yet")
+	}
+}
+
+func TestHardNodeAffinitySelector(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		ok   bool
+	}{
+		{
+			name: "no affinity at all",
+			pod:  &corev1.Pod{},
+			ok:   false,
+		},
+		{
+			name: "single term with a supported operator",
+			pod: podWithRequiredAffinity(corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+				},
+			}),
+			ok: true,
+		},
+		{
+			name: "multiple OR'd terms can't be narrowed to one Node",
+			pod: podWithRequiredAffinity(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+				}},
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+				}},
+			),
+			ok: false,
+		},
+		{
+			name: "field selector terms aren't label-based",
+			pod: podWithRequiredAffinity(corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"n1"}},
+				},
+			}),
+			ok: false,
+		},
+		{
+			name: "unsupported operator (Gt) can't become a label selector",
+			pod: podWithRequiredAffinity(corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "cpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}},
+				},
+			}),
+			ok: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := hardNodeAffinitySelector(tt.pod)
+			if ok != tt.ok {
+				t.Errorf("hardNodeAffinitySelector() ok = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHardNodeAffinitySelectorMatchesTheRequirement(t *testing.T) {
+	pod := podWithRequiredAffinity(corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+			{Key: "legacy", Operator: corev1.NodeSelectorOpDoesNotExist},
+		},
+	})
+
+	sel, ok := hardNodeAffinitySelector(pod)
+	if !ok {
+		t.Fatal("hardNodeAffinitySelector() ok = false, want true")
+	}
+	if !sel.Matches(labelSet{"zone": "a"}) {
+		t.Error("selector should match a Node labeled zone=a with no legacy label")
+	}
+	if sel.Matches(labelSet{"zone": "a", "legacy": "true"}) {
+		t.Error("selector should not match a Node that has the legacy label")
+	}
+	if sel.Matches(labelSet{"zone": "b"}) {
+		t.Error("selector should not match a Node labeled zone=b")
+	}
+}
+
+// labelSet is the minimal labels.Labels implementation needed to exercise
+// the selector returned by hardNodeAffinitySelector without pulling in a
+// real Node object.
+type labelSet map[string]string
+
+func (l labelSet) Has(key string) bool   { _, ok := l[key]; return ok }
+func (l labelSet) Get(key string) string { return l[key] }
+
+func TestNodeSelectorOperatorToSelection(t *testing.T) {
+	tests := []struct {
+		op   corev1.NodeSelectorOperator
+		want bool
+	}{
+		{corev1.NodeSelectorOpIn, true},
+		{corev1.NodeSelectorOpNotIn, true},
+		{corev1.NodeSelectorOpExists, true},
+		{corev1.NodeSelectorOpDoesNotExist, true},
+		{corev1.NodeSelectorOpGt, false},
+		{corev1.NodeSelectorOpLt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.op), func(t *testing.T) {
+			_, ok := nodeSelectorOperatorToSelection(tt.op)
+			if ok != tt.want {
+				t.Errorf("nodeSelectorOperatorToSelection(%s) ok = %v, want %v", tt.op, ok, tt.want)
+			}
+		})
+	}
+}